@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+
+	"threebody/physics"
+)
+
+// runCluster 演化一个生成的 N 体团，定时打印维里比、能量误差和包含 10%、50%、90% 天体的半径，
+// 用来检查初始条件是否平衡、冷塌缩何时反弹
+func runCluster(args []string) error {
+	fs := flag.NewFlagSet("cluster", flag.ExitOnError)
+	spec := fs.String("cluster", "plummer", "cluster generator: plummer, disk or collapse, optionally with :N[,seed]")
+	name := fs.String("integrator", "verlet", "integrator name")
+	dt := fs.Float64("dt", 0.005, "time step in simulation units")
+	duration := fs.Float64("t", 10, "simulated time to run")
+	softening := fs.Float64("softening", 0.02, "gravitational softening length")
+	forces := fs.String("forces", "", "force laws, e.g. pm:8,128 for the periodic particle-mesh solver (empty = direct-sum gravity)")
+	every := fs.Float64("every", 0.5, "print a line every this much simulated time")
+	fs.Parse(args)
+
+	p, err := physics.ParseCluster(*spec)
+	if err != nil {
+		return err
+	}
+	integ, err := physics.NewIntegrator(*name)
+	if err != nil {
+		return err
+	}
+	if *dt <= 0 || *every <= 0 {
+		return fmt.Errorf("dt and every must be positive")
+	}
+	s := p.New()
+	s.Softening = *softening
+	if *forces != "" {
+		if s.Forces, err = physics.ParseForces(*forces); err != nil {
+			return err
+		}
+	}
+	if err := physics.CheckIntegrator(integ, s); err != nil {
+		return err
+	}
+	e0 := s.Energy()
+
+	fmt.Printf("%s: %d bodies, %s\n", p.Name, len(s.Bodies), p.Description)
+	fmt.Printf("%8s %8s %10s %8s %8s %8s\n", "t", "Q", "dE/E0", "r10", "r50", "r90")
+	print := func() {
+		r := percentileRadii(s, 0.1, 0.5, 0.9)
+		fmt.Printf("%8.3f %8.4f %10.2e %8.4f %8.4f %8.4f\n",
+			s.Time, s.KineticEnergy()/-s.PotentialEnergy(), (s.Energy()-e0)/math.Abs(e0), r[0], r[1], r[2])
+	}
+	print()
+	perLine := max(int(*every / *dt + 0.5), 1)
+	n := int(*duration / *dt + 0.5)
+	for k := 1; k <= n; k++ {
+		integ.Step(s, *dt)
+		s.WrapPeriodic()
+		if k%perLine == 0 {
+			print()
+		}
+	}
+	return nil
+}
+
+// percentileRadii 返回以质心为中心、包含各个比例的有质量天体的半径。
+// 按个数而不是质量计，这样盘的中心天体不会把所有半径都压成零
+func percentileRadii(s *physics.System, fractions ...float64) []float64 {
+	com, _ := s.CenterOfMass()
+	var r []float64
+	for _, i := range s.Massive() {
+		r = append(r, s.Bodies[i].Position.Sub(com).Length())
+	}
+	sort.Float64s(r)
+	radii := make([]float64, len(fractions))
+	for k, f := range fractions {
+		radii[k] = r[min(int(f*float64(len(r))), len(r)-1)]
+	}
+	return radii
+}
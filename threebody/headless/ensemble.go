@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"threebody/physics"
+)
+
+// ensembleResult 是一次随机三体运行的结果
+type ensembleResult struct {
+	run         int
+	seed        int64
+	outcome     physics.Outcome
+	time        float64 // 瓦解时间，未瓦解时为 -t
+	escaperMass float64
+	escapeSpeed float64
+	energyError float64 // 结束时的相对能量误差
+}
+
+// runEnsemble 在所有 CPU 上并行跑大量随机三体，统计瓦解时间和逃逸者质量的分布
+func runEnsemble(args []string) error {
+	fs := flag.NewFlagSet("ensemble", flag.ExitOnError)
+	runs := fs.Int("runs", 1000, "number of random initial conditions")
+	seed := fs.Int64("seed", 1, "base random seed; run i uses seed+i")
+	integrator := fs.String("integrator", "verlet", "integrator name")
+	dt := fs.Float64("dt", 0.001, "time step in simulation units")
+	tmax := fs.Float64("t", 200, "give up on a run after this much simulated time")
+	softening := fs.Float64("softening", 0, "gravitational softening length")
+	every := fs.Int("every", 100, "check for escapes every N steps")
+	var p physics.TripleParams
+	fs.Float64Var(&p.MassMin, "mass-min", 0.5, "lower bound of the uniform mass distribution")
+	fs.Float64Var(&p.MassMax, "mass-max", 1.5, "upper bound of the uniform mass distribution")
+	fs.Float64Var(&p.Virial, "virial", 0, "virial ratio T/|W| of the initial conditions")
+	fs.Float64Var(&p.Spin, "spin", 0, "angular momentum parameter in [-1, 1]")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of parallel workers")
+	out := fs.String("o", "ensemble.csv", "write per-run results as CSV to this file")
+	hist := fs.String("hist", "ensemble-summary.txt", "write the summary histograms to this file")
+	bins := fs.Int("bins", 20, "number of histogram bins")
+	maxError := fs.Float64("max-error", 0.01, "leave runs with a larger relative energy error out of the summary")
+	fs.Parse(args)
+
+	if _, err := physics.NewIntegrator(*integrator); err != nil {
+		return err
+	}
+	if *runs <= 0 || *workers <= 0 || *dt <= 0 || *bins <= 0 || *every <= 0 {
+		return fmt.Errorf("runs, workers, dt, bins and every must be positive")
+	}
+	if p.MassMin <= 0 || p.MassMax < p.MassMin || p.Spin < -1 || p.Spin > 1 || p.Virial < 0 {
+		return fmt.Errorf("invalid initial condition distribution %+v", p)
+	}
+
+	jobs := make(chan int)
+	results := make([]ensembleResult, *runs)
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// 每次运行用新的积分器，自适应步长等内部状态不会带到下一次，
+				// 结果和任务怎么分给 worker 无关
+				integ, _ := physics.NewIntegrator(*integrator)
+				s := physics.RandomTriple(rand.New(rand.NewSource(*seed+int64(i))), p)
+				s.Softening = *softening
+				results[i] = lifetime(s, integ, *dt, *tmax, *every)
+				results[i].run = i
+				results[i].seed = *seed + int64(i)
+			}
+		}()
+	}
+	for i := 0; i < *runs; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := writeEnsemble(*out, results); err != nil {
+		return err
+	}
+	f, err := os.Create(*hist)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	summarizeEnsemble(io.MultiWriter(f, os.Stdout), results, *bins, *maxError)
+	return nil
+}
+
+// lifetime 积分直到有天体逃逸或超过 tmax
+func lifetime(s *physics.System, integ physics.Integrator, dt, tmax float64, every int) ensembleResult {
+	e0 := s.Energy()
+	r := ensembleResult{outcome: physics.OutcomeInteracting, time: -tmax}
+	n := int(tmax/dt + 0.5)
+	for k := 1; k <= n; k++ {
+		integ.Step(s, dt)
+		if k%every != 0 {
+			continue
+		}
+		if c := physics.Classify(s); c.Outcome.Final() {
+			r.outcome = c.Outcome
+			r.time = c.Time
+			r.escaperMass = s.Bodies[c.Escaper].Mass
+			r.escapeSpeed = c.EscapeSpeed
+			break
+		}
+	}
+	if !r.outcome.Final() {
+		r.outcome = physics.Classify(s).Outcome
+	}
+	r.energyError = math.Abs((s.Energy() - e0) / e0)
+	return r
+}
+
+func writeEnsemble(path string, results []ensembleResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"run", "seed", "outcome", "disruption_time", "escaper_mass", "escape_speed", "energy_error"})
+	for _, r := range results {
+		w.Write([]string{
+			strconv.Itoa(r.run), strconv.FormatInt(r.seed, 10), r.outcome.String(),
+			ftoa(r.time), ftoa(r.escaperMass), ftoa(r.escapeSpeed), ftoa(r.energyError),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// summarizeEnsemble 输出瓦解比例、中位寿命以及瓦解时间和逃逸者质量的直方图。
+// 能量误差超过 maxError 的运行不可信，不计入统计。
+func summarizeEnsemble(w io.Writer, results []ensembleResult, bins int, maxError float64) {
+	var times, masses []float64
+	rejected := 0
+	for _, r := range results {
+		switch {
+		case r.energyError > maxError:
+			rejected++
+		case r.outcome.Final():
+			times = append(times, r.time)
+			masses = append(masses, r.escaperMass)
+		}
+	}
+	kept := len(results) - rejected
+	fmt.Fprintf(w, "runs: %d  rejected (energy error > %g): %d  disrupted: %d of %d\n",
+		len(results), maxError, rejected, len(times), kept)
+	if len(times) == 0 {
+		return
+	}
+	sort.Float64s(times)
+	fmt.Fprintf(w, "disruption time: median %.3f  min %.3f  max %.3f\n",
+		times[len(times)/2], times[0], times[len(times)-1])
+	fmt.Fprintln(w, "\ndisruption time histogram")
+	histogram(w, times, bins)
+	fmt.Fprintln(w, "\nescaper mass histogram")
+	histogram(w, masses, bins)
+}
+
+// histogram 以文本柱状图输出 xs 的分布
+func histogram(w io.Writer, xs []float64, bins int) {
+	lo, hi := xs[0], xs[0]
+	for _, x := range xs {
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+	width := (hi - lo) / float64(bins)
+	if width == 0 {
+		width = 1
+	}
+	counts := make([]int, bins)
+	peak := 0
+	for _, x := range xs {
+		k := min(int((x-lo)/width), bins-1)
+		counts[k]++
+		peak = max(peak, counts[k])
+	}
+	for k, c := range counts {
+		fmt.Fprintf(w, "%10.4f - %10.4f %6d %s\n",
+			lo+float64(k)*width, lo+float64(k+1)*width, c, strings.Repeat("#", 50*c/peak))
+	}
+}
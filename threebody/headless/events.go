@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+
+	"threebody/physics"
+)
+
+// runEvents 运行场景并把近心点、远心点和接触事件写成 CSV 日志
+func runEvents(args []string) error {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "pythagorean")
+	out := fs.String("o", "events.csv", "write the event log as CSV to this file")
+	stop := fs.Bool("stop-on-contact", false, "stop at the first contact event")
+	fs.Parse(args)
+
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"time", "kind", "a", "b", "distance"})
+
+	contact := false
+	d := physics.NewEventDetector(s)
+	d.On(func(e physics.Event) {
+		w.Write([]string{ftoa(e.Time), e.Kind.String(), e.A, e.B, ftoa(e.Distance)})
+		if e.Kind == physics.EventContact {
+			fmt.Println(e)
+			contact = true
+		}
+	})
+	n := sc.steps()
+	for k := 0; k < n && !(contact && *stop); k++ {
+		integ.Step(s, sc.dt)
+		d.Check(s)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	counts := map[physics.EventKind]int{}
+	for _, e := range d.Events {
+		counts[e.Kind]++
+	}
+	fmt.Printf("%s: %d events until t=%.3f (%d pericenter, %d apocenter, %d contact)\n", sc.name(), len(d.Events), s.Time,
+		counts[physics.EventPericenter], counts[physics.EventApocenter], counts[physics.EventContact])
+	if len(d.Events) > 0 {
+		fmt.Printf("closest approach: %s\n", d.Closest)
+	}
+	return nil
+}
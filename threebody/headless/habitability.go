@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"threebody/physics"
+)
+
+// runHabitability 模拟带行星的三日系统，导出气候样本和纪元时间线
+func runHabitability(args []string) error {
+	fs := flag.NewFlagSet("habitability", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "trisolaris")
+	host := fs.Int("planet", -1, "add a planet around this sun (counting massive bodies only) when the preset has none")
+	orbit := fs.Float64("planet-orbit", 0.15, "initial orbit radius of the added planet")
+	seed := fs.Int64("seed", 1, "orbital phase seed for the added planet")
+	every := fs.Int("every", 50, "record a climate sample every N steps")
+	minEra := fs.Float64("min-era", 0.5, "eras shorter than this merge into the previous one")
+	samplesPath := fs.String("samples", "", "write climate samples as CSV to this file")
+	erasPath := fs.String("o", "eras.csv", "write the era timeline as CSV to this file")
+	fs.Parse(args)
+
+	if *every <= 0 {
+		return fmt.Errorf("every must be positive, got %d", *every)
+	}
+
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	planet := s.Index("planet")
+	if planet < 0 {
+		if *host < 0 || *host >= len(s.Massive()) {
+			return fmt.Errorf("%s has no planet; pass -planet with a sun index", sc.name())
+		}
+		// -planet 数的是有质量天体，不是 Bodies 的下标
+		planet = s.AddPlanet(s.Massive()[*host], *orbit, *seed)
+	}
+	model := physics.NewClimateModel(s, planet)
+	timeline := physics.EraTimeline{MinDuration: *minEra}
+
+	var samples *csv.Writer
+	if *samplesPath != "" {
+		f, err := os.Create(*samplesPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		samples = csv.NewWriter(f)
+		samples.Write([]string{"time", "flux", "temperature", "suns", "flying_star", "era"})
+	}
+
+	record := func() {
+		c := model.Sample(s)
+		timeline.Add(c)
+		if samples != nil {
+			samples.Write([]string{
+				ftoa(c.Time), ftoa(c.Flux), ftoa(c.Temperature),
+				strconv.Itoa(c.Suns), strconv.FormatBool(c.FlyingStar), c.Era.String(),
+			})
+		}
+	}
+	record()
+	n := sc.steps()
+	for k := 1; k <= n; k++ {
+		integ.Step(s, sc.dt)
+		if k%*every == 0 {
+			record()
+		}
+	}
+	if samples != nil {
+		samples.Flush()
+		if err := samples.Error(); err != nil {
+			return err
+		}
+	}
+
+	if err := writeEras(*erasPath, timeline.Eras); err != nil {
+		return err
+	}
+	for _, e := range timeline.Eras {
+		fmt.Printf("%-8s %9.2f - %9.2f  T %4.0f..%4.0f K  flying-star %d  multi-sun %d\n",
+			e.Kind, e.Start, e.End, e.MinTemp, e.MaxTemp, e.FlyingStar, e.MultiSun)
+	}
+	return nil
+}
+
+func writeEras(path string, eras []physics.Era) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"era", "start", "end", "duration", "min_temp", "max_temp", "flying_star_samples", "multi_sun_samples"})
+	for _, e := range eras {
+		w.Write([]string{
+			e.Kind.String(), ftoa(e.Start), ftoa(e.End), ftoa(e.Duration()),
+			ftoa(e.MinTemp), ftoa(e.MaxTemp), strconv.Itoa(e.FlyingStar), strconv.Itoa(e.MultiSun),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func ftoa(x float64) string {
+	return strconv.FormatFloat(x, 'g', 8, 64)
+}
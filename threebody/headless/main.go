@@ -0,0 +1,146 @@
+// headless 在没有窗口的情况下运行模拟并把结果写到文件。
+//
+//	go run ./headless <command> [flags]
+//
+// 每个子命令用 -h 查看参数。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"threebody/physics"
+)
+
+// commands 是所有子命令
+var commands = map[string]func(args []string) error{
+	"cluster":       runCluster,
+	"ensemble":      runEnsemble,
+	"events":        runEvents,
+	"habitability":  runHabitability,
+	"lyapunov":      runLyapunov,
+	"outcome":       runOutcome,
+	"poincare":      runPoincare,
+	"reversibility": runReversibility,
+	"precession":    runPrecession,
+	"reference":     runReference,
+	"trajectory":    runTrajectory,
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "usage: headless <command> [flags]\ncommands: %v\n", names)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// scenario 是各个子命令共用的场景参数
+type scenario struct {
+	preset     string
+	cluster    string
+	integrator string
+	dt         float64
+	duration   float64
+	softening  float64
+	forces     string
+	charges    string
+	massLoss   string
+}
+
+func (sc *scenario) register(fs *flag.FlagSet, preset string) {
+	fs.StringVar(&sc.preset, "preset", preset, "initial condition preset")
+	fs.StringVar(&sc.cluster, "cluster", "", "generate an N-body cluster instead of the preset: plummer, disk or collapse, optionally with :N[,seed]")
+	fs.StringVar(&sc.integrator, "integrator", "verlet", "integrator name")
+	fs.Float64Var(&sc.dt, "dt", 0.001, "time step in simulation units")
+	fs.Float64Var(&sc.duration, "t", 100, "simulated time to run")
+	fs.Float64Var(&sc.softening, "softening", 0, "gravitational softening length")
+	fs.StringVar(&sc.forces, "forces", "", "force laws separated by ';', e.g. gravity;coulomb:1;drag:0.1 (empty = gravity only)")
+	fs.StringVar(&sc.charges, "charges", "", "comma-separated charges assigned to bodies in order")
+	fs.StringVar(&sc.massLoss, "mass-loss", "", "time-varying masses separated by ';', e.g. A:exp:50;C:sudden:20,0.6")
+}
+
+// build 按参数创建系统和积分器
+func (sc *scenario) build() (*physics.System, physics.Integrator, error) {
+	var (
+		p   physics.Preset
+		err error
+	)
+	if sc.cluster != "" {
+		p, err = physics.ParseCluster(sc.cluster)
+	} else {
+		p, err = physics.LookupPreset(sc.preset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if sc.dt <= 0 {
+		return nil, nil, fmt.Errorf("dt must be positive, got %g", sc.dt)
+	}
+	s := p.New()
+	s.Softening = sc.softening
+	if sc.forces != "" {
+		if s.Forces, err = physics.ParseForces(sc.forces); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.SetCharges(sc.charges); err != nil {
+		return nil, nil, err
+	}
+	if err := s.SetMassLoss(sc.massLoss); err != nil {
+		return nil, nil, err
+	}
+	integ, err := newIntegrator(sc.integrator, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, integ, nil
+}
+
+// newIntegrator 按名字创建积分 s 用的积分器：s 有质量变化时包装成 MassVarying，
+// 并检查积分器支持 s 的力
+func newIntegrator(name string, s *physics.System) (physics.Integrator, error) {
+	integ, err := physics.NewIntegrator(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.MassLoss) > 0 {
+		integ = physics.MassVarying{Integrator: integ}
+	}
+	if err := physics.CheckIntegrator(integ, s); err != nil {
+		return nil, err
+	}
+	return integ, nil
+}
+
+// name 返回场景名，用 -cluster 时是生成器的参数
+func (sc *scenario) name() string {
+	if sc.cluster != "" {
+		return sc.cluster
+	}
+	return sc.preset
+}
+
+// steps 返回跑完 duration 需要的步数
+func (sc *scenario) steps() int {
+	return int(sc.duration/sc.dt + 0.5)
+}
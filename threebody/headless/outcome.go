@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"threebody/physics"
+)
+
+// runOutcome 运行场景直到有天体逃逸（或到达 -t），打印每次状态变化和最终判定
+func runOutcome(args []string) error {
+	fs := flag.NewFlagSet("outcome", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "pythagorean")
+	every := fs.Int("every", 100, "classify the system every N steps")
+	cont := fs.Bool("continue", false, "keep integrating after an escape instead of stopping")
+	fs.Parse(args)
+
+	if *every <= 0 {
+		return fmt.Errorf("every must be positive, got %d", *every)
+	}
+
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	last := physics.Classify(s)
+	fmt.Println(last)
+	n := sc.steps()
+	for k := 1; k <= n; k++ {
+		integ.Step(s, sc.dt)
+		if k%*every != 0 {
+			continue
+		}
+		c := physics.Classify(s)
+		if c.Outcome == last.Outcome {
+			continue
+		}
+		fmt.Println(c)
+		last = c
+		if c.Outcome.Final() && !*cont {
+			break
+		}
+	}
+	fmt.Printf("final state: %s\n", last.Outcome)
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"threebody/physics"
+)
+
+// runPrecession 同时运行带 1PN 修正和纯牛顿的两份场景，
+// 比较一颗天体绕中心天体的近心点进动，并与广义相对论的预言对照
+func runPrecession(args []string) error {
+	fs := flag.NewFlagSet("precession", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "mercury")
+	body := fs.String("body", "mercury", "orbiting body")
+	center := fs.String("center", "sun", "central body")
+	fs.Parse(args)
+
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	i, j := s.Index(*body), s.Index(*center)
+	if i < 0 || j < 0 {
+		return fmt.Errorf("%s has no bodies named %q and %q", sc.name(), *body, *center)
+	}
+	c := 0.0
+	newton := s.Clone()
+	newton.Forces = nil
+	for _, f := range s.Forces {
+		if pn, ok := f.(physics.PostNewtonian); ok {
+			c = pn.C
+		} else {
+			newton.Forces = append(newton.Forces, f)
+		}
+	}
+	if c == 0 {
+		return fmt.Errorf("no 1pn term in the forces; try -forces \"gravity;1pn:10\"")
+	}
+	integN, err := physics.NewIntegratorLike(integ)
+	if err != nil {
+		return err
+	}
+
+	// 初始的牛顿轨道根数，用于广义相对论的预言
+	m := s.Bodies[i].Mass + s.Bodies[j].Mass
+	r := s.Bodies[i].Position.Sub(s.Bodies[j].Position)
+	v := s.Bodies[i].Velocity.Sub(s.Bodies[j].Velocity)
+	a := 1 / (2/r.Length() - v.Length2()/(s.G*m))
+	e := s.EccentricityVector(i, j).Length()
+
+	rel := physics.Pericenters{I: i, J: j}
+	flat := physics.Pericenters{I: i, J: j}
+	n := sc.steps()
+	for k := 0; k < n; k++ {
+		integ.Step(s, sc.dt)
+		integN.Step(newton, sc.dt)
+		rel.Update(s)
+		flat.Update(newton)
+	}
+
+	fmt.Printf("orbit  time        omega_1pn     omega_newton\n")
+	for k := 0; k < min(len(rel.Angles), len(flat.Angles)); k++ {
+		fmt.Printf("%5d  %10.4f  %12.8f  %12.8f\n", k+1, rel.Times[k], rel.Angles[k], flat.Angles[k])
+	}
+	gr := physics.PrecessionGR(s.G, m, c, a, e)
+	fmt.Printf("a = %.6f  e = %.6f  c = %g\n", a, e, c)
+	fmt.Printf("precession per orbit: 1pn %.6e rad, newton %.6e rad, GR prediction %.6e rad (%.2f%% off)\n",
+		rel.PerOrbit(), flat.PerOrbit(), gr, 100*math.Abs(rel.PerOrbit()/gr-1))
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+
+	"threebody/physics"
+)
+
+// runReference 用普通积分器和高精度参考积分器同时跑同一个初始条件，
+// 报告两者的相空间距离第一次超过容差的时刻
+func runReference(args []string) error {
+	fs := flag.NewFlagSet("reference", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "figure8")
+	ref := fs.String("ref", "rk4-big", "reference integrator (rk4-big, rk4-kahan, ...)")
+	refine := fs.Int("refine", 4, "reference takes this many substeps per step")
+	tol := fs.Float64("tol", 1e-6, "phase-space distance that counts as diverged")
+	every := fs.Int("every", 100, "compare every this many steps")
+	out := fs.String("o", "", "write the distance samples as CSV to this file")
+	fs.Parse(args)
+
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	refInteg, err := newIntegrator(*ref, s)
+	if err != nil {
+		return err
+	}
+
+	d := physics.CompareWithReference(s, integ, refInteg, sc.dt, *refine, sc.duration, *tol, *every)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := csv.NewWriter(f)
+		w.Write([]string{"time", "distance"})
+		for _, p := range d.Samples {
+			w.Write([]string{ftoa(p.Time), ftoa(p.Distance)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	}
+
+	maxDist := 0.0
+	for _, p := range d.Samples {
+		maxDist = max(maxDist, p.Distance)
+	}
+	fmt.Printf("%s: %s (dt = %g) against %s (dt = %g) until t=%g\n",
+		sc.name(), integ.Name(), sc.dt, refInteg.Name(), sc.dt/float64(max(*refine, 1)), sc.duration)
+	if d.Diverged {
+		fmt.Printf("diverged beyond %g at t=%.4f, max distance %.3e\n", *tol, d.Time, maxDist)
+	} else {
+		fmt.Printf("stayed within %g, max distance %.3e\n", *tol, maxDist)
+	}
+	return nil
+}
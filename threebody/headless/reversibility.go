@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"threebody/physics"
+)
+
+// runReversibility 对一组积分器做往返积分，打印回到起点后与初始条件的距离
+func runReversibility(args []string) error {
+	fs := flag.NewFlagSet("reversibility", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "figure8")
+	// rk4-big 慢两个数量级，默认不跑，需要时在 -integrators 里写上
+	var names []string
+	for _, name := range physics.IntegratorNames() {
+		if name != "rk4-big" {
+			names = append(names, name)
+		}
+	}
+	list := fs.String("integrators", strings.Join(names, ","), "comma-separated integrators to check (rk4-big is slow and left out by default)")
+	fs.Parse(args)
+
+	if sc.massLoss != "" {
+		return fmt.Errorf("mass loss is not time-reversible, drop -mass-loss")
+	}
+	s, _, err := sc.build()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: forward %g, flip velocities, back %g (dt = %g)\n", sc.name(), sc.duration, sc.duration, sc.dt)
+	fmt.Printf("%-12s %8s  %-12s %s\n", "integrator", "steps", "distance", "dE/E0")
+	for _, name := range strings.Split(*list, ",") {
+		integ, err := newIntegrator(strings.TrimSpace(name), s)
+		if err != nil {
+			return err
+		}
+		r := physics.Reversibility(s, integ, sc.dt, sc.duration)
+		fmt.Printf("%-12s %8d  %-12.3e %.3e\n", integ.Name(), r.Steps, r.Distance, r.EnergyError)
+	}
+	return nil
+}
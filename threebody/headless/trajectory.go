@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"threebody/physics"
+	"threebody/svgplot"
+)
+
+// runTrajectory 跑完整个场景，把有质量天体和行星的完整轨迹画成 SVG
+func runTrajectory(args []string) error {
+	fs := flag.NewFlagSet("trajectory", flag.ExitOnError)
+	var sc scenario
+	sc.register(fs, "figure8")
+	out := fs.String("o", "trajectories.svg", "output SVG file")
+	every := fs.Int("every", 10, "record positions every this many steps")
+	title := fs.String("title", "", "title drawn in the corner (default: scenario and integrator)")
+	size := fs.Int("size", 800, "width and height of the image in pixels")
+	fs.Parse(args)
+
+	// svgplot 四周各留 40 像素，再小就放不下轨迹
+	if *size < 100 {
+		return fmt.Errorf("size must be at least 100 px, got %d", *size)
+	}
+	s, integ, err := sc.build()
+	if err != nil {
+		return err
+	}
+	t := physics.NewTrajectories(s)
+	t.Every = *every
+	for k := 0; k < sc.steps(); k++ {
+		integ.Step(s, sc.dt)
+		t.Record(s)
+	}
+	t.Finish(s)
+	if *title == "" {
+		*title = fmt.Sprintf("%s, %s, dt = %g", sc.name(), integ.Name(), sc.dt)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := svgplot.Write(f, t, svgplot.Options{Width: *size, Height: *size, Title: *title}); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	n := 0
+	for _, p := range t.Paths {
+		n += len(p)
+	}
+	fmt.Printf("%s: %d trajectories, %d points, t = %g to %g -> %s\n", sc.name(), len(t.Paths), n, t.Start, t.End, *out)
+	return nil
+}
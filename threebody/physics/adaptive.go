@@ -0,0 +1,131 @@
+package physics
+
+import "math"
+
+// Dormand-Prince 5(4) 的 Butcher 表
+var (
+	dpA = [7][6]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+	// 五阶解减四阶解的误差权重；五阶解的权重就是 dpA 的最后一行
+	dpE = [7]float64{
+		35.0/384 - 5179.0/57600, 0, 500.0/1113 - 7571.0/16695, 125.0/192 - 393.0/640,
+		-2187.0/6784 + 92097.0/339200, 11.0/84 - 187.0/2100, -1.0 / 40,
+	}
+)
+
+// Adaptive 是带步长控制的 Dormand-Prince 5(4) 积分器。
+// Step(s, dt) 总是恰好推进 dt，内部按局部误差自动细分子步，
+// 近距离交会时自动缩小步长，远离时放大。
+type Adaptive struct {
+	Tol      float64 // 每个子步允许的相对误差，为零时取 1e-12
+	MinStep  float64 // 子步下限，为零时取 dt×1e-9；到达下限后不再拒绝
+	Substeps int     // 累计接受的子步数，用于观察步长变化
+
+	h      float64 // 上一次建议的子步长
+	sys    *System // h 是在哪个系统、哪个 dt 下得到的，换了就重新从 dt 开始
+	dt     float64
+	x0, v0 []Vec2
+	x, v   []Vec2
+	kx, kv [7][]Vec2
+}
+
+func (a *Adaptive) Name() string { return "adaptive" }
+
+func (a *Adaptive) Step(s *System, dt float64) {
+	tol := a.Tol
+	if tol == 0 {
+		tol = 1e-12
+	}
+	minStep := a.MinStep
+	if minStep == 0 {
+		minStep = dt * 1e-9
+	}
+	if s != a.sys || dt != a.dt || !(a.h > 0 && a.h <= dt) {
+		a.h = dt
+		a.sys, a.dt = s, dt
+	}
+
+	start := s.Time
+	for left := dt; left > dt*1e-12; {
+		h := math.Min(a.h, left)
+		err := a.trial(s, h, tol)
+		// 误差是 NaN（比如两个天体重合）时也拒绝，步长缩到五分之一
+		if !(err <= 1) && h > minStep {
+			shrink := 0.2
+			if !math.IsNaN(err) {
+				shrink = math.Max(0.2, 0.9*math.Pow(err, -0.2))
+			}
+			a.h = math.Max(h*shrink, minStep)
+			continue
+		}
+		// 接受子步
+		for i := range s.Bodies {
+			s.Bodies[i].Position = a.x[i]
+			s.Bodies[i].Velocity = a.v[i]
+		}
+		s.Time += h
+		left -= h
+		a.Substeps++
+		grow := 5.0
+		if math.IsNaN(err) {
+			grow = 1
+		} else if err > 0 {
+			grow = math.Min(5, 0.9*math.Pow(err, -0.2))
+		}
+		// 最后一个子步可能被截短，截短的子步只用来缩小建议步长
+		if truncated := h < a.h; !truncated || grow < 1 {
+			a.h = math.Max(h*grow, minStep)
+		}
+	}
+	s.Time = start + dt
+}
+
+// trial 从 s 的当前状态试走一个长度为 h 的子步，结果放在 a.x、a.v，
+// 返回按 tol 归一化的误差（≤ 1 表示可以接受）
+func (a *Adaptive) trial(s *System, h, tol float64) float64 {
+	n := len(s.Bodies)
+	a.x0 = s.Positions(a.x0)
+	a.v0 = s.Velocities(a.v0)
+	a.x = grow(a.x, n)
+	a.v = grow(a.v, n)
+	for st := range a.kx {
+		a.kx[st] = grow(a.kx[st], n)
+		a.kv[st] = grow(a.kv[st], n)
+	}
+
+	for st := 0; st < 7; st++ {
+		for i := 0; i < n; i++ {
+			x, v := a.x0[i], a.v0[i]
+			for j := 0; j < st; j++ {
+				if c := dpA[st][j]; c != 0 {
+					x = x.Add(a.kx[j][i].Mult(h * c))
+					v = v.Add(a.kv[j][i].Mult(h * c))
+				}
+			}
+			a.x[i], a.v[i] = x, v
+			a.kx[st][i] = v
+		}
+		s.Accelerations(a.x, a.v, a.kv[st])
+	}
+
+	// 第 7 阶段的求值点就是五阶解（FSAL），a.x、a.v 已经是结果。
+	// 误差只看有质量天体，这样加入测试粒子不会改变有质量天体的步长和轨迹。
+	errMax := 0.0
+	for _, i := range s.Massive() {
+		var ex, ev Vec2
+		for st := 0; st < 7; st++ {
+			ex = ex.Add(a.kx[st][i].Mult(h * dpE[st]))
+			ev = ev.Add(a.kv[st][i].Mult(h * dpE[st]))
+		}
+		errMax = math.Max(errMax, ex.Length()/(tol*(1+a.x0[i].Length())))
+		errMax = math.Max(errMax, ev.Length()/(tol*(1+a.v0[i].Length())))
+	}
+	return errMax
+}
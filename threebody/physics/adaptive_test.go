@@ -0,0 +1,52 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// nanOnce 在前 calls 次求值时给出 NaN 加速度，之后没有力
+type nanOnce struct {
+	calls int
+}
+
+func (f *nanOnce) Name() string { return "nan-once" }
+
+func (f *nanOnce) Accumulate(s *System, pos, vel, acc []Vec2) {
+	if f.calls > 0 {
+		f.calls--
+		for i := range acc {
+			acc[i] = Vec2{math.NaN(), math.NaN()}
+		}
+	}
+}
+
+// 误差是 NaN 的子步要被拒绝，不能把 NaN 写进状态
+func TestAdaptiveRejectsNaN(t *testing.T) {
+	s := NewSystem(Body{Name: "a", Mass: 1, Velocity: Vec2{1, 0}})
+	s.Forces = []ForceLaw{&nanOnce{calls: 7}}
+	a := &Adaptive{}
+	a.Step(s, 0.1)
+	if p := s.Bodies[0].Position; math.IsNaN(p.X) || math.Abs(p.X-0.1) > 1e-12 {
+		t.Errorf("position %v after a NaN trial, want (0.1, 0)", p)
+	}
+	if a.Substeps < 1 || math.IsNaN(a.h) {
+		t.Errorf("substeps %d, suggested step %g", a.Substeps, a.h)
+	}
+}
+
+// 换一个系统后建议步长重新从 dt 开始，前一个系统的近距离交会不会拖慢后一个
+func TestAdaptiveReset(t *testing.T) {
+	tight := keplerOrbit{mu: 1, a: 1, e: 0.99}.system(-0.01)
+	a := &Adaptive{Tol: 1e-6}
+	a.Step(tight, 0.02)
+	if a.h > 0.005 {
+		t.Fatalf("suggested step %g after pericenter, expected it to shrink", a.h)
+	}
+	wide := keplerOrbit{mu: 1, a: 1, e: 0}.system(0)
+	a.Substeps = 0
+	a.Step(wide, 0.02)
+	if a.Substeps != 1 {
+		t.Errorf("%d substeps on a new system, want 1", a.Substeps)
+	}
+}
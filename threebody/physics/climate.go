@@ -0,0 +1,189 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// 三体世界的气候模型：行星是一个测试粒子，地表温度由各个太阳的光度和距离决定。
+// 光度与通量都用模拟单位，温度用开尔文，以初始时刻的通量作为"舒适"的参考。
+
+// EraKind 是纪元类型
+type EraKind int
+
+const (
+	EraStable  EraKind = iota // 恒纪元：单日主导、温度宜居
+	EraChaotic                // 乱纪元
+)
+
+func (k EraKind) String() string {
+	if k == EraStable {
+		return "stable"
+	}
+	return "chaotic"
+}
+
+// LuminosityOf 返回天体光度，未设置时按质光关系 L = M^3.5 估算
+func LuminosityOf(b *Body) float64 {
+	if b.Luminosity > 0 || b.IsTest() {
+		return b.Luminosity
+	}
+	return math.Pow(b.Mass, 3.5)
+}
+
+// ClimateModel 描述行星的日照和温度模型
+type ClimateModel struct {
+	Planet           int     // 行星（测试粒子）的下标
+	ReferenceFlux    float64 // 温度为 ReferenceTemp 时的通量
+	ReferenceTemp    float64 // 参考温度（K）
+	MinTemp, MaxTemp float64 // 宜居温度范围（K）
+
+	DominantFraction   float64 // 单颗太阳占总通量的比例超过它才算"单日主导"
+	SunFraction        float64 // 单颗太阳的通量超过 ReferenceFlux 的这个比例才算天上的"日"
+	FlyingStarFraction float64 // 所有太阳的通量都低于 ReferenceFlux 的这个比例时是"飞星"
+}
+
+// NewClimateModel 以当前时刻行星受到的通量为参考创建气候模型
+func NewClimateModel(s *System, planet int) ClimateModel {
+	m := ClimateModel{
+		Planet:             planet,
+		ReferenceTemp:      288,
+		MinTemp:            263,
+		MaxTemp:            318,
+		DominantFraction:   0.9,
+		SunFraction:        0.05,
+		FlyingStarFraction: 0.01,
+	}
+	m.ReferenceFlux, _ = m.flux(s)
+	return m
+}
+
+// flux 返回行星受到的总通量和每颗太阳的贡献
+func (m ClimateModel) flux(s *System) (float64, []float64) {
+	p := s.Bodies[m.Planet].Position
+	src := s.Massive()
+	parts := make([]float64, len(src))
+	total := 0.0
+	for k, i := range src {
+		b := &s.Bodies[i]
+		d2 := b.Position.Sub(p).Length2()
+		if d2 == 0 {
+			continue
+		}
+		parts[k] = LuminosityOf(b) / (4 * math.Pi * d2)
+		total += parts[k]
+	}
+	return total, parts
+}
+
+// ClimateSample 是某一时刻的气候状态
+type ClimateSample struct {
+	Time        float64
+	Flux        float64
+	Temperature float64 // K
+	Suns        int     // 天上"日"的个数
+	FlyingStar  bool    // 所有太阳都远得像星星
+	Era         EraKind
+}
+
+// MultiSun 判断是否为多日凌空
+func (c ClimateSample) MultiSun() bool {
+	return c.Suns >= 2
+}
+
+// Sample 计算当前时刻的气候
+func (m ClimateModel) Sample(s *System) ClimateSample {
+	total, parts := m.flux(s)
+	c := ClimateSample{Time: s.Time, Flux: total, FlyingStar: true}
+	if m.ReferenceFlux > 0 {
+		c.Temperature = m.ReferenceTemp * math.Pow(total/m.ReferenceFlux, 0.25)
+	}
+	dominant := 0.0
+	for _, f := range parts {
+		if f >= m.SunFraction*m.ReferenceFlux {
+			c.Suns++
+		}
+		if f >= m.FlyingStarFraction*m.ReferenceFlux {
+			c.FlyingStar = false
+		}
+		dominant = math.Max(dominant, f)
+	}
+	c.Era = EraChaotic
+	if total > 0 && dominant/total >= m.DominantFraction &&
+		c.Temperature >= m.MinTemp && c.Temperature <= m.MaxTemp {
+		c.Era = EraStable
+	}
+	return c
+}
+
+// Era 是一段连续的恒纪元或乱纪元
+type Era struct {
+	Kind             EraKind
+	Start, End       float64
+	MinTemp, MaxTemp float64
+	FlyingStar       int // 飞星样本数
+	MultiSun         int // 多日凌空样本数
+}
+
+// Duration 返回纪元长度
+func (e Era) Duration() float64 {
+	return e.End - e.Start
+}
+
+// EraTimeline 把气候样本合并成纪元序列。
+// 短于 MinDuration 的纪元并入前一个纪元，避免边界附近来回跳动。
+type EraTimeline struct {
+	MinDuration float64
+	Eras        []Era
+}
+
+// Add 追加一个样本
+func (t *EraTimeline) Add(c ClimateSample) {
+	n := len(t.Eras)
+	if n > 0 && t.Eras[n-1].Kind != c.Era && n >= 2 && t.Eras[n-1].Duration() < t.MinDuration {
+		// 上一个纪元太短，并入更早的纪元
+		prev, last := &t.Eras[n-2], t.Eras[n-1]
+		prev.End = last.End
+		prev.MinTemp = math.Min(prev.MinTemp, last.MinTemp)
+		prev.MaxTemp = math.Max(prev.MaxTemp, last.MaxTemp)
+		prev.FlyingStar += last.FlyingStar
+		prev.MultiSun += last.MultiSun
+		t.Eras = t.Eras[:n-1]
+		n--
+	}
+	if n == 0 || t.Eras[n-1].Kind != c.Era {
+		t.Eras = append(t.Eras, Era{
+			Kind:    c.Era,
+			Start:   c.Time,
+			End:     c.Time,
+			MinTemp: c.Temperature,
+			MaxTemp: c.Temperature,
+		})
+		n++
+	}
+	e := &t.Eras[n-1]
+	e.End = c.Time
+	e.MinTemp = math.Min(e.MinTemp, c.Temperature)
+	e.MaxTemp = math.Max(e.MaxTemp, c.Temperature)
+	if c.FlyingStar {
+		e.FlyingStar++
+	}
+	if c.MultiSun() {
+		e.MultiSun++
+	}
+}
+
+// AddPlanet 在 host 号天体周围的圆轨道上加入一颗名为 "planet" 的行星（测试粒子），
+// 轨道相位由 seed 决定。返回行星下标。
+func (s *System) AddPlanet(host int, radius float64, seed int64) int {
+	h := &s.Bodies[host]
+	th := rand.New(rand.NewSource(seed)).Float64() * 2 * math.Pi
+	dir := Vec2{math.Cos(th), math.Sin(th)}
+	v := math.Sqrt(s.G * h.Mass / radius)
+	s.Bodies = append(s.Bodies, Body{
+		Name:     "planet",
+		Position: h.Position.Add(dir.Mult(radius)),
+		Velocity: h.Velocity.Add(Vec2{-dir.Y, dir.X}.Mult(v)),
+	})
+	return len(s.Bodies) - 1
+}
@@ -0,0 +1,34 @@
+package physics
+
+import "testing"
+
+func TestEraTimelineMergesShortEras(t *testing.T) {
+	tl := EraTimeline{MinDuration: 1}
+	kinds := []EraKind{
+		EraStable, EraStable, EraStable, EraStable, // 0..3
+		EraChaotic,           // 4：太短，并入恒纪元
+		EraStable, EraStable, // 5..6
+		EraChaotic, EraChaotic, EraChaotic, // 7..9
+	}
+	for i, k := range kinds {
+		tl.Add(ClimateSample{Time: float64(i), Temperature: 288, Era: k})
+	}
+	if len(tl.Eras) != 2 {
+		t.Fatalf("got %d eras, want 2: %+v", len(tl.Eras), tl.Eras)
+	}
+	if e := tl.Eras[0]; e.Kind != EraStable || e.Start != 0 || e.End != 6 {
+		t.Errorf("first era = %+v, want stable 0..6", e)
+	}
+	if e := tl.Eras[1]; e.Kind != EraChaotic || e.Start != 7 || e.End != 9 {
+		t.Errorf("second era = %+v, want chaotic 7..9", e)
+	}
+}
+
+func TestClimateReferenceIsTemperate(t *testing.T) {
+	s := trisolaris()
+	m := NewClimateModel(s, s.Index("planet"))
+	c := m.Sample(s)
+	if c.Temperature != m.ReferenceTemp || c.Era != EraStable || c.Suns != 1 {
+		t.Errorf("initial sample = %+v, want a temperate single-sun stable era", c)
+	}
+}
@@ -0,0 +1,272 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Cluster 是一种 N 体团初始条件的生成器。同样的 n 和 seed 总是生成同样的系统，
+// 生成的系统都已经换到质心系（质心在原点、总动量为零）。
+type Cluster struct {
+	Name        string
+	Description string
+	New         func(n int, seed int64) *System
+	New3        func(n int, seed int64) *System3
+}
+
+var clusters = []Cluster{
+	{"plummer", "Plummer sphere in virial equilibrium (Henon units)", Plummer, Plummer3},
+	{"disk", "exponential disk of stars rotating around a central mass", ExponentialDisk, ExponentialDisk3},
+	{"collapse", "uniform cold collapse starting at rest", ColdCollapse, ColdCollapse3},
+}
+
+// DefaultClusterStars 是 ParseCluster 没有给出个数时的星数
+const DefaultClusterStars = 300
+
+// Clusters 返回所有 N 体团生成器
+func Clusters() []Cluster {
+	return clusters
+}
+
+// ParseCluster 解析 "名字[:N[,seed]]"，例如 "plummer"、"disk:1000" 或 "collapse:500,7"，
+// 返回按这些参数生成系统的场景。省略时 N 取 DefaultClusterStars，seed 取 1。
+func ParseCluster(spec string) (Preset, error) {
+	c, n, seed, err := parseCluster(spec)
+	if err != nil {
+		return Preset{}, err
+	}
+	return Preset{spec, c.Description, func() *System { return c.New(n, seed) }}, nil
+}
+
+// ParseCluster3 和 ParseCluster 一样，但生成三维系统
+func ParseCluster3(spec string) (Preset3, error) {
+	c, n, seed, err := parseCluster(spec)
+	if err != nil {
+		return Preset3{}, err
+	}
+	return Preset3{spec, c.Description, func() *System3 { return c.New3(n, seed) }}, nil
+}
+
+func parseCluster(spec string) (Cluster, int, int64, error) {
+	name, args, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	n, seed := DefaultClusterStars, int64(1)
+	if args != "" {
+		nText, seedText, hasSeed := strings.Cut(args, ",")
+		var err error
+		if n, err = strconv.Atoi(strings.TrimSpace(nText)); err != nil || n < 2 {
+			return Cluster{}, 0, 0, fmt.Errorf("cluster %q: need at least 2 stars", spec)
+		}
+		if hasSeed {
+			if seed, err = strconv.ParseInt(strings.TrimSpace(seedText), 10, 64); err != nil {
+				return Cluster{}, 0, 0, fmt.Errorf("cluster %q: %v", spec, err)
+			}
+		}
+	}
+	names := make([]string, len(clusters))
+	for i, c := range clusters {
+		if c.Name == name {
+			return c, n, seed, nil
+		}
+		names[i] = c.Name
+	}
+	return Cluster{}, 0, 0, fmt.Errorf("unknown cluster %q (have %v)", name, names)
+}
+
+// starRadius 是生成的星的绘制半径
+const starRadius = 0.005
+
+// Plummer3 按 Aarseth、Hénon 和 Wielen (1974) 的方法抽样 n 颗等质量星组成的 Plummer 球：
+// 半径由累积质量分布反解得到，速度大小用舍选法按分布函数抽样，方向各向同性。
+// 最后把速度缩放到维里比恰好为 1/2，并换到 Hénon 单位（G = M = 1，E = -1/4），
+// 消除有限 N 带来的抽样涨落。
+func Plummer3(n int, seed int64) *System3 {
+	rng := rand.New(rand.NewSource(seed))
+	// 先在 a = 1 的 Plummer 单位下抽样，最后整体缩放到 Hénon 单位
+	const a = 3 * math.Pi / 16
+	s := NewSystem3()
+	for k := 0; k < n; k++ {
+		var r float64
+		for {
+			// 截断在 10a 以内，约丢掉 0.15% 的质量
+			if x := rng.Float64(); x > 0 {
+				if r = 1 / math.Sqrt(math.Pow(x, -2.0/3)-1); r < 10 {
+					break
+				}
+			}
+		}
+		// g(q) = q²(1-q²)^3.5 的最大值约为 0.092
+		var q float64
+		for {
+			q = rng.Float64()
+			if 0.1*rng.Float64() < q*q*math.Pow(1-q*q, 3.5) {
+				break
+			}
+		}
+		v := q * math.Sqrt2 * math.Pow(1+r*r, -0.25)
+		s.Bodies = append(s.Bodies, Body3{
+			Mass:     1 / float64(n),
+			Radius:   starRadius,
+			Position: randomDir3(rng).Mult(r * a),
+			Velocity: randomDir3(rng).Mult(v / math.Sqrt(a)),
+		})
+	}
+	s.ToCenterOfMassFrame()
+	s.virialize(0.5)
+	s.henonUnits()
+	return s
+}
+
+// Plummer 是投影到 xy 平面上的 Plummer 球。平面内的距离比空间距离短，
+// 投影后按平面内的势能把速度重新缩放到维里比 1/2，再换到 Hénon 单位
+func Plummer(n int, seed int64) *System {
+	s := Plummer3(n, seed).flatten()
+	s.virialize(0.5)
+	s.henonUnits()
+	return s
+}
+
+// ExponentialDisk3 生成绕质量为 1 的中心天体旋转的指数盘：n 颗星共 0.1 的质量，
+// 面密度 ∝ exp(-r/0.5)，只取 0.1 到 4 倍标长之间（太靠近中心的星周期太短）。速度取包含在 r 以内的质量给出的圆轨道速度，
+// 加上 5% 的随机弥散；盘的厚度是标长的 5%
+func ExponentialDisk3(n int, seed int64) *System3 {
+	const (
+		central  = 1.0
+		diskMass = 0.1
+		scale    = 0.5
+		sigma    = 0.05
+	)
+	rng := rand.New(rand.NewSource(seed))
+	s := NewSystem3(Body3{Name: "core", Mass: central, Radius: 0.03})
+	for k := 0; k < n; k++ {
+		// 面密度为指数分布时 r/标长 服从形状参数为 2 的伽马分布
+		var r float64
+		for {
+			if r = -scale * math.Log((1-rng.Float64())*(1-rng.Float64())); r > 0.1*scale && r < 4*scale {
+				break
+			}
+		}
+		x := r / scale
+		enclosed := central + diskMass*(1-(1+x)*math.Exp(-x))
+		vc := math.Sqrt(s.G * enclosed / r)
+		th := rng.Float64() * 2 * math.Pi
+		dir := Vec3{math.Cos(th), math.Sin(th), 0}
+		tangent := Vec3{-dir.Y, dir.X, 0}
+		s.Bodies = append(s.Bodies, Body3{
+			Mass:     diskMass / float64(n),
+			Radius:   starRadius,
+			Position: dir.Mult(r).Add(Vec3{0, 0, rng.NormFloat64() * sigma * scale}),
+			Velocity: tangent.Mult(vc * (1 + sigma*rng.NormFloat64())).
+				Add(dir.Mult(vc * sigma * rng.NormFloat64())).
+				Add(Vec3{0, 0, vc * sigma * rng.NormFloat64()}),
+		})
+	}
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+// ExponentialDisk 是 ExponentialDisk3 去掉厚度后的平面盘
+func ExponentialDisk(n int, seed int64) *System {
+	return ExponentialDisk3(n, seed).flatten()
+}
+
+// ColdCollapse3 在半径为 1 的球内均匀放置 n 颗总质量为 1 的静止星
+func ColdCollapse3(n int, seed int64) *System3 {
+	rng := rand.New(rand.NewSource(seed))
+	s := NewSystem3()
+	for k := 0; k < n; k++ {
+		s.Bodies = append(s.Bodies, Body3{
+			Mass:     1 / float64(n),
+			Radius:   starRadius,
+			Position: randomDir3(rng).Mult(math.Cbrt(rng.Float64())),
+		})
+	}
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+// ColdCollapse 在半径为 1 的圆盘内均匀放置 n 颗总质量为 1 的静止星
+func ColdCollapse(n int, seed int64) *System {
+	rng := rand.New(rand.NewSource(seed))
+	s := NewSystem()
+	for k := 0; k < n; k++ {
+		r := math.Sqrt(rng.Float64())
+		th := rng.Float64() * 2 * math.Pi
+		s.Bodies = append(s.Bodies, Body{
+			Mass:     1 / float64(n),
+			Radius:   starRadius,
+			Position: Vec2{r * math.Cos(th), r * math.Sin(th)},
+		})
+	}
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+// randomDir3 返回各向同性分布的单位矢量
+func randomDir3(rng *rand.Rand) Vec3 {
+	z := 2*rng.Float64() - 1
+	th := rng.Float64() * 2 * math.Pi
+	rho := math.Sqrt(1 - z*z)
+	return Vec3{rho * math.Cos(th), rho * math.Sin(th), z}
+}
+
+// flatten 去掉 z 分量，得到 xy 平面上的二维系统
+func (s *System3) flatten() *System {
+	flat := NewSystem()
+	flat.G, flat.Softening, flat.Time = s.G, s.Softening, s.Time
+	for _, b := range s.Bodies {
+		flat.Bodies = append(flat.Bodies, Body{
+			Name: b.Name, Mass: b.Mass, Radius: b.Radius,
+			Position: b.Position.XY(), Velocity: b.Velocity.XY(),
+		})
+	}
+	flat.ToCenterOfMassFrame()
+	return flat
+}
+
+// virialize 缩放速度使维里比 T/|W| 等于 q
+func (s *System) virialize(q float64) {
+	w := -s.PotentialEnergy()
+	if t := s.KineticEnergy(); t > 0 {
+		f := math.Sqrt(q * w / t)
+		for i := range s.Bodies {
+			s.Bodies[i].Velocity = s.Bodies[i].Velocity.Mult(f)
+		}
+	}
+}
+
+// henonUnits 在系统束缚时缩放长度和速度使总能量为 -1/4（Hénon 单位）
+func (s *System) henonUnits() {
+	if e := s.Energy(); e < 0 {
+		// 长度放大 a 倍、速度缩小 √a 倍，能量变为 E/a
+		a := e / -0.25
+		for i := range s.Bodies {
+			b := &s.Bodies[i]
+			b.Position = b.Position.Mult(a)
+			b.Velocity = b.Velocity.Mult(1 / math.Sqrt(a))
+		}
+	}
+}
+
+func (s *System3) virialize(q float64) {
+	w := -s.PotentialEnergy()
+	if t := s.KineticEnergy(); t > 0 {
+		f := math.Sqrt(q * w / t)
+		for i := range s.Bodies {
+			s.Bodies[i].Velocity = s.Bodies[i].Velocity.Mult(f)
+		}
+	}
+}
+
+func (s *System3) henonUnits() {
+	if e := s.Energy(); e < 0 {
+		a := e / -0.25
+		for i := range s.Bodies {
+			b := &s.Bodies[i]
+			b.Position = b.Position.Mult(a)
+			b.Velocity = b.Velocity.Mult(1 / math.Sqrt(a))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package physics
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// 2000 颗星的 Plummer 球：维里比为 1/2，能量为 -1/4，半质量半径接近 0.769
+func TestPlummer3(t *testing.T) {
+	s := Plummer3(2000, 1)
+	w := -s.PotentialEnergy()
+	if q := s.KineticEnergy() / w; math.Abs(q-0.5) > 1e-12 {
+		t.Errorf("virial ratio %.3f, want 0.5", q)
+	}
+	if e := s.Energy(); math.Abs(e+0.25) > 1e-12 {
+		t.Errorf("energy %.4f, want -0.25", e)
+	}
+	r := make([]float64, len(s.Bodies))
+	for i, b := range s.Bodies {
+		r[i] = b.Position.Length()
+	}
+	sort.Float64s(r)
+	if rh := r[len(r)/2]; math.Abs(rh-0.769) > 0.04 {
+		t.Errorf("half-mass radius %.3f, want 0.769", rh)
+	}
+}
+
+func TestClusters(t *testing.T) {
+	for _, c := range Clusters() {
+		s, s3 := c.New(200, 3), c.New3(200, 3)
+		if again := c.New(200, 3); again.Bodies[7] != s.Bodies[7] {
+			t.Errorf("%s: same seed gave different stars", c.Name)
+		}
+		if c.New(200, 4).Bodies[7] == s.Bodies[7] {
+			t.Errorf("%s: different seeds gave the same star", c.Name)
+		}
+		com, v := s.CenterOfMass()
+		if com.Length() > 1e-12 || v.Length() > 1e-12 || s.Momentum().Length() > 1e-12 {
+			t.Errorf("%s: center of mass %v moving at %v", c.Name, com, v)
+		}
+		com3, v3 := s3.CenterOfMass()
+		if com3.Length() > 1e-12 || v3.Length() > 1e-12 {
+			t.Errorf("%s 3D: center of mass %v moving at %v", c.Name, com3, v3)
+		}
+		if s.Energy() >= 0 {
+			t.Errorf("%s: unbound, E = %g", c.Name, s.Energy())
+		}
+	}
+
+	// 平面 Plummer 在平面内的势能下处于维里平衡
+	s := Plummer(500, 1)
+	if q := s.KineticEnergy() / -s.PotentialEnergy(); math.Abs(q-0.5) > 1e-12 {
+		t.Errorf("2D plummer virial ratio %g", q)
+	}
+	// 盘里的星绕中心逆时针转
+	if l := ExponentialDisk(500, 1).AngularMomentum(); l <= 0 {
+		t.Errorf("disk angular momentum %g", l)
+	}
+}
+
+func TestParseCluster(t *testing.T) {
+	p, err := ParseCluster("disk:50,9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.New(); len(s.Bodies) != 51 || s.Bodies[0].Name != "core" {
+		t.Errorf("disk:50 gave %d bodies", len(s.Bodies))
+	}
+	p3, err := ParseCluster3("plummer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(p3.New().Bodies); n != DefaultClusterStars {
+		t.Errorf("plummer gave %d stars, want %d", n, DefaultClusterStars)
+	}
+	for _, bad := range []string{"galaxy", "plummer:1", "plummer:x", "plummer:10,y"} {
+		if _, err := ParseCluster(bad); err == nil {
+			t.Errorf("ParseCluster(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package physics
+
+import "math"
+
+// Elements 是 I 号天体相对 J 号天体的二体密切轨道根数
+type Elements struct {
+	I, J         int
+	Energy       float64 // 单位约化质量的二体能量，负值表示束缚
+	SemiMajor    float64 // 半长轴 a，非束缚时为负
+	Eccentricity float64
+	Period       float64 // 周期，非束缚时为 +Inf
+	Periapsis    float64 // 近心点方向角 ω（弧度），从 x 轴量起
+	Retrograde   bool    // 顺时针绕转
+}
+
+// Bound 判断是否为束缚轨道
+func (e Elements) Bound() bool {
+	return e.Energy < 0
+}
+
+// OrbitalElements 把 i、j 两个天体当成孤立两体，计算 i 相对 j 的密切轨道根数
+func (s *System) OrbitalElements(i, j int) Elements {
+	bi, bj := &s.Bodies[i], &s.Bodies[j]
+	mu := s.G * (bi.Mass + bj.Mass)
+	energy, dist, _ := twoBody(s.G, bj.Mass, bj.Position, bj.Velocity, bi.Mass, bi.Position, bi.Velocity)
+	ecc := s.EccentricityVector(i, j)
+	el := Elements{
+		I: i, J: j,
+		Energy:       energy,
+		SemiMajor:    -mu / (2 * energy),
+		Eccentricity: ecc.Length(),
+		Period:       math.Inf(1),
+		Periapsis:    math.Atan2(ecc.Y, ecc.X),
+		Retrograde:   bi.Position.Sub(bj.Position).Cross(bi.Velocity.Sub(bj.Velocity)) < 0,
+	}
+	if dist == 0 {
+		el.SemiMajor = 0
+	}
+	if el.Bound() {
+		el.Period = 2 * math.Pi * math.Sqrt(el.SemiMajor*el.SemiMajor*el.SemiMajor/mu)
+	}
+	return el
+}
+
+// TightestPair 在有质量天体中找出半长轴最小的束缚对，J 取两者中较重的一个。
+// 没有束缚对时 ok 为 false。
+func (s *System) TightestPair() (el Elements, ok bool) {
+	src := s.Massive()
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			i, j := src[a], src[b]
+			if s.Bodies[i].Mass > s.Bodies[j].Mass {
+				i, j = j, i
+			}
+			e := s.OrbitalElements(i, j)
+			if e.Bound() && (!ok || e.SemiMajor < el.SemiMajor) {
+				el, ok = e, true
+			}
+		}
+	}
+	return el, ok
+}
+
+// Ellipse 返回束缚轨道上均匀分布在真近点角上的 n 个点，坐标相对 J（焦点）
+func (e Elements) Ellipse(n int) []Vec2 {
+	p := e.SemiMajor * (1 - e.Eccentricity*e.Eccentricity)
+	pts := make([]Vec2, n)
+	for k := range pts {
+		nu := 2 * math.Pi * float64(k) / float64(n)
+		r := p / (1 + e.Eccentricity*math.Cos(nu))
+		th := e.Periapsis + nu
+		pts[k] = Vec2{r * math.Cos(th), r * math.Sin(th)}
+	}
+	return pts
+}
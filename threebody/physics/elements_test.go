@@ -0,0 +1,41 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrbitalElements(t *testing.T) {
+	const a, e, omega = 2.0, 0.3, 0.7
+	// 从近心点出发，近心点方向为 omega
+	dir := Vec2{math.Cos(omega), math.Sin(omega)}
+	rp := a * (1 - e)
+	vp := math.Sqrt(1.5 * (1 + e) / rp)
+	s := NewSystem(
+		Body{Name: "A", Mass: 1},
+		Body{Name: "B", Mass: 0.5, Position: dir.Mult(rp), Velocity: Vec2{-dir.Y, dir.X}.Mult(vp)},
+		Body{Name: "C", Mass: 0.1, Position: Vec2{100, 0}, Velocity: Vec2{0, 5}},
+	)
+	el, ok := s.TightestPair()
+	if !ok {
+		t.Fatal("no bound pair")
+	}
+	if el.I != 1 || el.J != 0 {
+		t.Errorf("pair %d-%d, want 1-0", el.I, el.J)
+	}
+	near := func(name string, got, want float64) {
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	near("a", el.SemiMajor, a)
+	near("e", el.Eccentricity, e)
+	near("omega", el.Periapsis, omega)
+	near("period", el.Period, 2*math.Pi*math.Sqrt(a*a*a/1.5))
+	if el.Retrograde {
+		t.Error("orbit reported as retrograde")
+	}
+	pts := el.Ellipse(4)
+	near("periapsis distance", pts[0].Length(), rp)
+	near("apoapsis distance", pts[2].Length(), a*(1+e))
+}
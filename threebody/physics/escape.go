@@ -0,0 +1,125 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Outcome 是三体系统当前所处的状态
+type Outcome int
+
+const (
+	OutcomeInteracting  Outcome = iota // 仍在相互作用
+	OutcomeHierarchical                // 层级三体：内双星加远处的束缚第三体
+	OutcomeBinaryEscape                // 双星加一个逃逸者
+	OutcomeDissolution                 // 完全瓦解：三个天体两两不束缚
+)
+
+var outcomeNames = [...]string{"interacting", "hierarchical triple", "binary + escaper", "dissolution"}
+
+func (o Outcome) String() string {
+	return outcomeNames[o]
+}
+
+// Final 判断是否为不可逆的终态（有天体逃逸）
+func (o Outcome) Final() bool {
+	return o == OutcomeBinaryEscape || o == OutcomeDissolution
+}
+
+const (
+	// EscapeRatio 是判定逃逸时外天体距离与内双星间距之比的下限，
+	// 太近时两体近似不可靠，近距离掠过会被误判为逃逸
+	EscapeRatio = 5.0
+	// HierarchyRatio 是判定层级三体的距离比下限
+	HierarchyRatio = 3.0
+)
+
+// Classification 是对三体状态的判定结果
+type Classification struct {
+	Outcome     Outcome
+	Time        float64
+	Binary      [2]int  // 内双星的两个下标
+	Escaper     int     // 外天体（逃逸者）的下标
+	EscapeSpeed float64 // 逃逸者相对双星质心在无穷远处的速度，未逃逸时为 0
+}
+
+func (c Classification) String() string {
+	switch c.Outcome {
+	case OutcomeBinaryEscape, OutcomeDissolution:
+		return fmt.Sprintf("%s at t=%.3f: body %d escapes with v_inf=%.4f", c.Outcome, c.Time, c.Escaper, c.EscapeSpeed)
+	case OutcomeHierarchical:
+		return fmt.Sprintf("%s at t=%.3f: binary %d-%d, outer body %d", c.Outcome, c.Time, c.Binary[0], c.Binary[1], c.Escaper)
+	}
+	return fmt.Sprintf("%s at t=%.3f", c.Outcome, c.Time)
+}
+
+// twoBody 把两个质点当成孤立两体，返回单位约化质量的能量、间距和径向速度 r·v
+func twoBody(g, m1 float64, r1, v1 Vec2, m2 float64, r2, v2 Vec2) (energy, dist, radial float64) {
+	r := r2.Sub(r1)
+	v := v2.Sub(v1)
+	dist = r.Length()
+	energy = 0.5*v.Length2() - g*(m1+m2)/dist
+	return energy, dist, r.Dot(v)
+}
+
+// Classify 判定有质量天体恰好为三个时系统所处的状态。
+// 先把离另外两个最远的天体当作外天体，用两体能量判断内双星和外天体是否束缚；
+// 外天体不束缚且正在远离、距离又足够远时判定为逃逸。
+func Classify(s *System) Classification {
+	c := Classification{Time: s.Time, Escaper: -1}
+	src := s.Massive()
+	if len(src) != 3 {
+		return c
+	}
+
+	// 挑出相对最孤立的天体作为外天体
+	best := -1.0
+	for k := 0; k < 3; k++ {
+		i, j := src[(k+1)%3], src[(k+2)%3]
+		bi, bj, bk := &s.Bodies[i], &s.Bodies[j], &s.Bodies[src[k]]
+		inner := bj.Position.Sub(bi.Position).Length()
+		com := bi.Position.Mult(bi.Mass).Add(bj.Position.Mult(bj.Mass)).Mult(1 / (bi.Mass + bj.Mass))
+		if ratio := bk.Position.Sub(com).Length() / inner; ratio > best {
+			best = ratio
+			c.Binary = [2]int{i, j}
+			c.Escaper = src[k]
+		}
+	}
+
+	bi, bj, bk := &s.Bodies[c.Binary[0]], &s.Bodies[c.Binary[1]], &s.Bodies[c.Escaper]
+	m := bi.Mass + bj.Mass
+	comR := bi.Position.Mult(bi.Mass).Add(bj.Position.Mult(bj.Mass)).Mult(1 / m)
+	comV := bi.Velocity.Mult(bi.Mass).Add(bj.Velocity.Mult(bj.Mass)).Mult(1 / m)
+
+	eIn, rIn, radialIn := twoBody(s.G, bi.Mass, bi.Position, bi.Velocity, bj.Mass, bj.Position, bj.Velocity)
+	eOut, rOut, radialOut := twoBody(s.G, m, comR, comV, bk.Mass, bk.Position, bk.Velocity)
+	ratio := rOut / rIn
+
+	switch {
+	case eOut > 0 && radialOut > 0 && eIn > 0 && radialIn > 0 && pairsReceding(s, src):
+		c.EscapeSpeed = math.Sqrt(2 * eOut)
+		c.Outcome = OutcomeDissolution
+	case eOut > 0 && radialOut > 0 && eIn < 0 && ratio > EscapeRatio:
+		c.EscapeSpeed = math.Sqrt(2 * eOut)
+		c.Outcome = OutcomeBinaryEscape
+	case eIn < 0 && eOut < 0 && ratio > HierarchyRatio:
+		c.Outcome = OutcomeHierarchical
+	default:
+		c.Escaper = -1
+	}
+	return c
+}
+
+// pairsReceding 判断是否任意两个天体都不束缚且正在相互远离
+func pairsReceding(s *System, src []int) bool {
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			bi, bj := &s.Bodies[src[a]], &s.Bodies[src[b]]
+			e, _, radial := twoBody(s.G, bi.Mass, bi.Position, bi.Velocity, bj.Mass, bj.Position, bj.Velocity)
+			if e <= 0 || radial <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassifyBinaryEscape(t *testing.T) {
+	s := hierarchical()
+	// 让第三颗星以远超逃逸速度的速度远离
+	s.Bodies[2].Position = Vec2{20, 0}
+	s.Bodies[2].Velocity = Vec2{2, 0}
+	c := Classify(s)
+	if c.Outcome != OutcomeBinaryEscape || c.Escaper != 2 {
+		t.Fatalf("Classify = %v, want body 2 escaping a binary", c)
+	}
+	if c.EscapeSpeed <= 0 || c.EscapeSpeed >= 2.5 || math.IsNaN(c.EscapeSpeed) {
+		t.Errorf("EscapeSpeed = %g, want in (0, 2.5)", c.EscapeSpeed)
+	}
+
+	// 同样的位置但速度朝向双星：还没有逃逸
+	s.Bodies[2].Velocity = Vec2{-2, 0}
+	if c := Classify(s); c.Outcome.Final() {
+		t.Errorf("approaching body classified as %v", c)
+	}
+}
+
+func TestClassifyHierarchical(t *testing.T) {
+	if c := Classify(hierarchical()); c.Outcome != OutcomeHierarchical || c.Escaper != 2 {
+		t.Errorf("Classify(hierarchical) = %v", c)
+	}
+}
@@ -0,0 +1,174 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// EventKind 是两体之间事件的种类
+type EventKind int
+
+const (
+	EventPericenter EventKind = iota // 距离取极小：r·v 由负变正
+	EventApocenter                   // 距离取极大：r·v 由正变负
+	EventContact                     // 距离减小到两者半径之和
+)
+
+var eventKindNames = [...]string{"pericenter", "apocenter", "contact"}
+
+func (k EventKind) String() string {
+	return eventKindNames[k]
+}
+
+// Event 是一次带时间戳的两体事件
+type Event struct {
+	Kind     EventKind
+	Time     float64
+	I, J     int    // 两个天体的下标
+	A, B     string // 两个天体的名字
+	Distance float64
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("t=%.6f %s %s-%s r=%.6g", e.Time, e.Kind, e.A, e.B, e.Distance)
+}
+
+// EventDetector 在每一步之后检查有质量天体两两之间的事件。
+// 它保存上一步的状态，用两端的位置和速度做三次 Hermite 插值，
+// 在步内对 r·v 和 |r| - (R_i + R_j) 求根，所以事件时刻不受步长限制，
+// 也不依赖具体的积分器。
+type EventDetector struct {
+	Events   []Event       // 检测到的事件，按时间顺序
+	Keep     int           // 大于零时 Events 只保留最近的 Keep 个，长时间运行不会无限增长
+	Closest  Event         // 迄今最近的一次距离极小（Kind 为 EventPericenter）
+	Handlers []func(Event) // 每个事件发生时依次调用，用于脚本化的场景
+
+	time     float64
+	pos, vel []Vec2
+}
+
+// NewEventDetector 以 s 的当前状态为起点创建检测器
+func NewEventDetector(s *System) *EventDetector {
+	d := &EventDetector{Closest: Event{Distance: math.Inf(1)}}
+	d.snapshot(s)
+	return d
+}
+
+// On 注册事件回调
+func (d *EventDetector) On(f func(Event)) {
+	d.Handlers = append(d.Handlers, f)
+}
+
+// Resync 在系统被外部修改（比如周期边界折回）之后重新记录状态，
+// 这一段不检查事件
+func (d *EventDetector) Resync(s *System) {
+	d.snapshot(s)
+}
+
+func (d *EventDetector) snapshot(s *System) {
+	d.time = s.Time
+	d.pos = s.Positions(d.pos)
+	d.vel = s.Velocities(d.vel)
+}
+
+// Check 在 s 推进一步之后调用，返回这一步里发生的事件（按时间排序），
+// 同时记入 Events 并调用回调。天体个数变了时只重新记录状态。
+func (d *EventDetector) Check(s *System) []Event {
+	defer d.snapshot(s)
+	h := s.Time - d.time
+	if len(d.pos) != len(s.Bodies) || h <= 0 {
+		return nil
+	}
+
+	var found []Event
+	src := s.Massive()
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			i, j := src[a], src[b]
+			seg := hermite{
+				r0: d.pos[i].Sub(d.pos[j]), v0: d.vel[i].Sub(d.vel[j]),
+				r1: s.Bodies[i].Position.Sub(s.Bodies[j].Position), v1: s.Bodies[i].Velocity.Sub(s.Bodies[j].Velocity),
+				h: h,
+			}
+			add := func(kind EventKind, theta float64) {
+				r, _ := seg.at(theta)
+				found = append(found, Event{
+					Kind: kind, Time: d.time + theta*h, I: i, J: j,
+					A: s.Bodies[i].Name, B: s.Bodies[j].Name, Distance: r.Length(),
+				})
+			}
+
+			radial := func(theta float64) float64 {
+				r, v := seg.at(theta)
+				return r.Dot(v)
+			}
+			g0, g1 := radial(0), radial(1)
+			if g0 < 0 && g1 >= 0 {
+				add(EventPericenter, bisect(radial, g0))
+			}
+			if g0 > 0 && g1 <= 0 {
+				add(EventApocenter, bisect(radial, g0))
+			}
+
+			if contact := s.Bodies[i].Radius + s.Bodies[j].Radius; contact > 0 {
+				gap := func(theta float64) float64 {
+					r, _ := seg.at(theta)
+					return r.Length() - contact
+				}
+				if g0 := gap(0); g0 > 0 && gap(1) <= 0 {
+					add(EventContact, bisect(gap, g0))
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(a, b int) bool { return found[a].Time < found[b].Time })
+	for _, e := range found {
+		if e.Kind == EventPericenter && e.Distance < d.Closest.Distance {
+			d.Closest = e
+		}
+		d.Events = append(d.Events, e)
+		if d.Keep > 0 && len(d.Events) > d.Keep {
+			d.Events = append(d.Events[:0], d.Events[len(d.Events)-d.Keep:]...)
+		}
+		for _, f := range d.Handlers {
+			f(e)
+		}
+	}
+	return found
+}
+
+// hermite 是一步之内相对运动的三次 Hermite 插值
+type hermite struct {
+	r0, v0, r1, v1 Vec2
+	h              float64
+}
+
+// at 返回 θ ∈ [0, 1] 处插值的相对位置和相对速度
+func (c hermite) at(theta float64) (Vec2, Vec2) {
+	t2, t3 := theta*theta, theta*theta*theta
+	r := c.r0.Mult(2*t3 - 3*t2 + 1).
+		Add(c.v0.Mult(c.h * (t3 - 2*t2 + theta))).
+		Add(c.r1.Mult(-2*t3 + 3*t2)).
+		Add(c.v1.Mult(c.h * (t3 - t2)))
+	v := c.r0.Mult((6*t2 - 6*theta) / c.h).
+		Add(c.v0.Mult(3*t2 - 4*theta + 1)).
+		Add(c.r1.Mult((-6*t2 + 6*theta) / c.h)).
+		Add(c.v1.Mult(3*t2 - 2*theta))
+	return r, v
+}
+
+// bisect 在 [0, 1] 上二分求 f 的根，g0 是 f(0)，要求 f(1) 与它异号或为零
+func bisect(f func(float64) float64, g0 float64) float64 {
+	lo, hi := 0.0, 1.0
+	for k := 0; k < 60; k++ {
+		mid := (lo + hi) / 2
+		if (f(mid) > 0) == (g0 > 0) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
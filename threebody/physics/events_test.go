@@ -0,0 +1,89 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 偏心率 0.5 的开普勒轨道，步长很粗时近心点、远心点的时刻和距离仍然准确
+func TestEventsKepler(t *testing.T) {
+	const e = 0.5
+	s := NewSystem(
+		Body{Name: "sun", Mass: 1},
+		Body{Name: "planet", Mass: 1e-9, Position: Vec2{1 + e, 0}, Velocity: Vec2{0, math.Sqrt((1 - e) / (1 + e))}},
+	)
+	d := NewEventDetector(s)
+	var seen int
+	d.On(func(Event) { seen++ })
+	integ := &RK4{}
+	for s.Time < 4*math.Pi+0.1 {
+		integ.Step(s, 0.01)
+		d.Check(s)
+	}
+	want := []struct {
+		kind EventKind
+		time float64
+		r    float64
+	}{
+		{EventPericenter, math.Pi, 1 - e},
+		{EventApocenter, 2 * math.Pi, 1 + e},
+		{EventPericenter, 3 * math.Pi, 1 - e},
+		{EventApocenter, 4 * math.Pi, 1 + e},
+	}
+	if len(d.Events) != len(want) || seen != len(want) {
+		t.Fatalf("got events %v (%d callbacks), want %d", d.Events, seen, len(want))
+	}
+	for k, w := range want {
+		got := d.Events[k]
+		if got.Kind != w.kind || math.Abs(got.Time-w.time) > 1e-6 || math.Abs(got.Distance-w.r) > 1e-6 {
+			t.Errorf("event %d = %v, want %s at t=%.6f r=%.6f", k, got, w.kind, w.time, w.r)
+		}
+	}
+	if c := d.Closest; c.Kind != EventPericenter || math.Abs(c.Distance-(1-e)) > 1e-6 {
+		t.Errorf("closest approach %v", d.Closest)
+	}
+
+	// Keep 只保留最近的事件，回调和 Closest 不受影响
+	s = NewSystem(
+		Body{Name: "sun", Mass: 1},
+		Body{Name: "planet", Mass: 1e-9, Position: Vec2{1 + e, 0}, Velocity: Vec2{0, math.Sqrt((1 - e) / (1 + e))}},
+	)
+	d = NewEventDetector(s)
+	d.Keep = 2
+	seen = 0
+	d.On(func(Event) { seen++ })
+	for s.Time < 4*math.Pi+0.1 {
+		integ.Step(s, 0.01)
+		d.Check(s)
+	}
+	if len(d.Events) != 2 || seen != 4 || d.Events[0].Kind != EventPericenter || d.Events[1].Kind != EventApocenter {
+		t.Errorf("with Keep = 2 got events %v (%d callbacks)", d.Events, seen)
+	}
+	if math.Abs(d.Closest.Distance-(1-e)) > 1e-6 {
+		t.Errorf("closest approach with Keep = 2: %v", d.Closest)
+	}
+}
+
+// 没有力时两球匀速相向运动，接触时刻可以直接算出
+func TestEventsContact(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "a", Mass: 1, Radius: 0.1, Position: Vec2{-1, 0}, Velocity: Vec2{1, 0}},
+		Body{Name: "b", Mass: 1, Radius: 0.1, Position: Vec2{1, 0}, Velocity: Vec2{-1, 0}},
+	)
+	s.Forces = []ForceLaw{UniformField{}}
+	d := NewEventDetector(s)
+	integ := &Verlet{}
+	for k := 0; k < 10; k++ {
+		integ.Step(s, 0.25)
+		d.Check(s)
+	}
+	if len(d.Events) != 2 {
+		t.Fatalf("got events %v, want contact then pericenter", d.Events)
+	}
+	if c := d.Events[0]; c.Kind != EventContact || math.Abs(c.Time-0.9) > 1e-9 {
+		t.Errorf("contact %v, want t=0.9", c)
+	}
+	if p := d.Events[1]; p.Kind != EventPericenter || math.Abs(p.Time-1) > 1e-9 || p.Distance > 1e-9 {
+		t.Errorf("closest approach %v, want t=1 r=0", p)
+	}
+}
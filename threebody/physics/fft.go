@@ -0,0 +1,59 @@
+package physics
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// fft 对长度为 2 的幂的 a 原地做基 2 快速傅里叶变换。
+// inverse 为 true 时做逆变换并除以长度。
+func fft(a []complex128, inverse bool) {
+	n := len(a)
+	if n <= 1 {
+		return
+	}
+	// 位反转重排
+	shift := 64 - bits.TrailingZeros(uint(n))
+	for i := range a {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); j > i {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u, v := a[start+k], a[start+k+size/2]*wk
+				a[start+k], a[start+k+size/2] = u+v, u-v
+				wk *= w
+			}
+		}
+	}
+	if inverse {
+		for i := range a {
+			a[i] /= complex(float64(n), 0)
+		}
+	}
+}
+
+// fft2 对按行存放的 n×n 网格做二维变换，col 是长度为 n 的缓冲
+func fft2(grid []complex128, n int, inverse bool, col []complex128) {
+	for y := 0; y < n; y++ {
+		fft(grid[y*n:(y+1)*n], inverse)
+	}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			col[y] = grid[y*n+x]
+		}
+		fft(col, inverse)
+		for y := 0; y < n; y++ {
+			grid[y*n+x] = col[y]
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ForceLaw 是一种作用在天体上的力。Accumulate 把天体位于 pos、速度为 vel 时
+// 这种力产生的加速度累加到 acc 上（不清零）。
+type ForceLaw interface {
+	Name() string
+	Accumulate(s *System, pos, vel, acc []Vec2)
+}
+
+// Conservative 是有势能的力，System.PotentialEnergy 会把它们的势能加起来
+type Conservative interface {
+	ForceLaw
+	Potential(s *System) float64
+}
+
+// Newtonian 是牛顿引力，使用 System 的 G 和 Softening。
+// System.Forces 为空时默认只有它。
+type Newtonian struct{}
+
+func (Newtonian) Name() string { return "gravity" }
+
+// Accumulate 只让有质量的天体作为引力源，测试粒子只受力不施力，
+// 所以开销是 O(N_massive × N_total)，加几千个测试粒子也很便宜。
+func (Newtonian) Accumulate(s *System, pos, vel, acc []Vec2) {
+	eps2 := s.Softening * s.Softening
+	src := s.Massive()
+	for i := range pos {
+		var a Vec2
+		for _, j := range src {
+			if j == i {
+				continue
+			}
+			d := pos[j].Sub(pos[i])
+			r2 := d.Length2() + eps2
+			if r2 == 0 {
+				continue
+			}
+			a = a.Add(d.Mult(s.G * s.Bodies[j].Mass / (r2 * math.Sqrt(r2))))
+		}
+		acc[i] = acc[i].Add(a)
+	}
+}
+
+// Potential 返回引力势能（与 Softening 一致）
+func (Newtonian) Potential(s *System) float64 {
+	eps2 := s.Softening * s.Softening
+	src := s.Massive()
+	e := 0.0
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			bi, bj := &s.Bodies[src[a]], &s.Bodies[src[b]]
+			r := math.Sqrt(bj.Position.Sub(bi.Position).Length2() + eps2)
+			e -= s.G * bi.Mass * bj.Mass / r
+		}
+	}
+	return e
+}
+
+// Coulomb 是带电天体之间的静电力 F = K·q1·q2/r²，同号相斥、异号相吸。
+// 只作用于有质量的带电天体，软化长度与引力相同。
+type Coulomb struct {
+	K float64
+}
+
+func (Coulomb) Name() string { return "coulomb" }
+
+func (c Coulomb) Accumulate(s *System, pos, vel, acc []Vec2) {
+	eps2 := s.Softening * s.Softening
+	for i := range pos {
+		bi := &s.Bodies[i]
+		if bi.Charge == 0 || bi.IsTest() {
+			continue
+		}
+		for j := range pos {
+			bj := &s.Bodies[j]
+			if j == i || bj.Charge == 0 || bj.IsTest() {
+				continue
+			}
+			d := pos[i].Sub(pos[j])
+			r2 := d.Length2() + eps2
+			if r2 == 0 {
+				continue
+			}
+			acc[i] = acc[i].Add(d.Mult(c.K * bi.Charge * bj.Charge / (bi.Mass * r2 * math.Sqrt(r2))))
+		}
+	}
+}
+
+// Potential 返回静电势能 Σ K·q1·q2/r
+func (c Coulomb) Potential(s *System) float64 {
+	eps2 := s.Softening * s.Softening
+	e := 0.0
+	for i := range s.Bodies {
+		bi := &s.Bodies[i]
+		if bi.Charge == 0 || bi.IsTest() {
+			continue
+		}
+		for j := i + 1; j < len(s.Bodies); j++ {
+			bj := &s.Bodies[j]
+			if bj.Charge == 0 || bj.IsTest() {
+				continue
+			}
+			r := math.Sqrt(bj.Position.Sub(bi.Position).Length2() + eps2)
+			e += c.K * bi.Charge * bj.Charge / r
+		}
+	}
+	return e
+}
+
+// Spring 是连接两个命名天体的胡克弹簧，F = -K·(r - Length)
+type Spring struct {
+	A, B   string // 两端天体的名字
+	K      float64
+	Length float64 // 自然长度
+}
+
+func (Spring) Name() string { return "spring" }
+
+// ends 返回两端天体的下标，任一端不存在或是测试粒子时 ok 为 false
+func (sp Spring) ends(s *System) (i, j int, ok bool) {
+	i, j = s.Index(sp.A), s.Index(sp.B)
+	if i < 0 || j < 0 || i == j || s.Bodies[i].IsTest() || s.Bodies[j].IsTest() {
+		return 0, 0, false
+	}
+	return i, j, true
+}
+
+func (sp Spring) Accumulate(s *System, pos, vel, acc []Vec2) {
+	i, j, ok := sp.ends(s)
+	if !ok {
+		return
+	}
+	d := pos[j].Sub(pos[i])
+	r := d.Length()
+	if r == 0 {
+		return
+	}
+	// f 是沿 i→j 方向作用在 i 上的力，拉伸时为正
+	f := d.Mult(sp.K * (r - sp.Length) / r)
+	acc[i] = acc[i].Add(f.Mult(1 / s.Bodies[i].Mass))
+	acc[j] = acc[j].Sub(f.Mult(1 / s.Bodies[j].Mass))
+}
+
+// Potential 返回弹性势能 K·(r - Length)²/2
+func (sp Spring) Potential(s *System) float64 {
+	i, j, ok := sp.ends(s)
+	if !ok {
+		return 0
+	}
+	x := s.Bodies[j].Position.Sub(s.Bodies[i].Position).Length() - sp.Length
+	return 0.5 * sp.K * x * x
+}
+
+// Drag 是阻力 a = -Linear·v - Quadratic·|v|·v，按单位质量给出，
+// 对测试粒子同样有效。阻力不守恒，没有势能，总能量会随之下降。
+type Drag struct {
+	Linear, Quadratic float64
+}
+
+func (Drag) Name() string { return "drag" }
+
+func (d Drag) Accumulate(s *System, pos, vel, acc []Vec2) {
+	for i := range vel {
+		v := vel[i]
+		acc[i] = acc[i].Sub(v.Mult(d.Linear + d.Quadratic*v.Length()))
+	}
+}
+
+// UniformField 是处处相同的外加加速度场，比如地表重力
+type UniformField struct {
+	G Vec2
+}
+
+func (UniformField) Name() string { return "field" }
+
+func (u UniformField) Accumulate(s *System, pos, vel, acc []Vec2) {
+	for i := range acc {
+		acc[i] = acc[i].Add(u.G)
+	}
+}
+
+// Potential 返回势能 -Σ m·G·r（以原点为零点）
+func (u UniformField) Potential(s *System) float64 {
+	e := 0.0
+	for i := range s.Bodies {
+		e -= s.Bodies[i].Mass * u.G.Dot(s.Bodies[i].Position)
+	}
+	return e
+}
+
+// ParseForces 解析用分号分隔的力的列表，每一项是 "名字:参数,参数,..."：
+//
+//	gravity
+//	coulomb:K
+//	spring:A,B,K,Length
+//	drag:Linear[,Quadratic]
+//	field:Gx,Gy
+//	1pn:C
+//	pm:Box,Grid
+//
+// 例如 "gravity;coulomb:1;drag:0.05"。空字符串返回 nil，即只有默认的引力。
+func ParseForces(spec string) ([]ForceLaw, error) {
+	var laws []ForceLaw
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, argText, _ := strings.Cut(item, ":")
+		var args []string
+		if argText != "" {
+			args = strings.Split(argText, ",")
+		}
+		// nums 把 args[from:] 解析成数字，要求个数在 [lo, hi] 之间
+		nums := func(from, lo, hi int) ([]float64, error) {
+			if n := len(args) - from; n < lo || n > hi {
+				return nil, fmt.Errorf("force %q: wrong number of arguments", item)
+			}
+			v := make([]float64, len(args)-from)
+			for k := range v {
+				x, err := strconv.ParseFloat(strings.TrimSpace(args[from+k]), 64)
+				if err != nil {
+					return nil, fmt.Errorf("force %q: %v", item, err)
+				}
+				v[k] = x
+			}
+			return v, nil
+		}
+
+		var law ForceLaw
+		switch name {
+		case "gravity":
+			if _, err := nums(0, 0, 0); err != nil {
+				return nil, err
+			}
+			law = Newtonian{}
+		case "coulomb":
+			v, err := nums(0, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			law = Coulomb{K: v[0]}
+		case "spring":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("force %q: spring wants A,B,K,Length", item)
+			}
+			v, err := nums(2, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			law = Spring{A: strings.TrimSpace(args[0]), B: strings.TrimSpace(args[1]), K: v[0], Length: v[1]}
+		case "drag":
+			v, err := nums(0, 1, 2)
+			if err != nil {
+				return nil, err
+			}
+			d := Drag{Linear: v[0]}
+			if len(v) == 2 {
+				d.Quadratic = v[1]
+			}
+			law = d
+		case "field":
+			v, err := nums(0, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			law = UniformField{G: Vec2{v[0], v[1]}}
+		case "1pn":
+			v, err := nums(0, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			if v[0] <= 0 {
+				return nil, fmt.Errorf("force %q: speed of light must be positive", item)
+			}
+			law = PostNewtonian{C: v[0]}
+		case "pm":
+			v, err := nums(0, 2, 2)
+			if err != nil {
+				return nil, err
+			}
+			grid := int(v[1])
+			if v[0] <= 0 || grid < 4 || float64(grid) != v[1] || grid&(grid-1) != 0 {
+				return nil, fmt.Errorf("force %q: need a positive box and a power-of-two grid of at least 4", item)
+			}
+			law = &ParticleMesh{Box: v[0], Grid: grid}
+		default:
+			return nil, fmt.Errorf("unknown force %q (have gravity, coulomb, spring, drag, field, 1pn, pm)", name)
+		}
+		laws = append(laws, law)
+	}
+	return laws, nil
+}
+
+// SetCharges 按天体顺序设置电荷，spec 是逗号分隔的数字，个数不能超过天体数，
+// 没列出的天体电荷不变
+func (s *System) SetCharges(spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	items := strings.Split(spec, ",")
+	if len(items) > len(s.Bodies) {
+		return fmt.Errorf("%d charges for %d bodies", len(items), len(s.Bodies))
+	}
+	for i, item := range items {
+		q, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil {
+			return fmt.Errorf("charge %d: %v", i, err)
+		}
+		s.Bodies[i].Charge = q
+	}
+	return nil
+}
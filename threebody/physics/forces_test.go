@@ -0,0 +1,86 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseForces(t *testing.T) {
+	laws, err := ParseForces("gravity; coulomb:2; spring:A,B,5,1; drag:0.1,0.01; field:0,-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ForceLaw{
+		Newtonian{},
+		Coulomb{K: 2},
+		Spring{A: "A", B: "B", K: 5, Length: 1},
+		Drag{Linear: 0.1, Quadratic: 0.01},
+		UniformField{G: Vec2{0, -1}},
+	}
+	if len(laws) != len(want) {
+		t.Fatalf("got %d laws, want %d", len(laws), len(want))
+	}
+	for k := range want {
+		if laws[k] != want[k] {
+			t.Errorf("law %d = %#v, want %#v", k, laws[k], want[k])
+		}
+	}
+	for _, bad := range []string{"magic", "coulomb", "spring:A,B,1", "field:1"} {
+		if _, err := ParseForces(bad); err == nil {
+			t.Errorf("ParseForces(%q) succeeded", bad)
+		}
+	}
+}
+
+// 没有设置 Forces 时和显式只用引力完全一样
+func TestDefaultForcesAreGravity(t *testing.T) {
+	a, _ := LookupPreset("figure8")
+	s1, s2 := a.New(), a.New()
+	s2.Forces = []ForceLaw{Newtonian{}}
+	for k := 0; k < 1000; k++ {
+		(&RK4{}).Step(s1, 0.001)
+		(&RK4{}).Step(s2, 0.001)
+	}
+	for i := range s1.Bodies {
+		if s1.Bodies[i].Position != s2.Bodies[i].Position {
+			t.Fatalf("body %d differs: %v vs %v", i, s1.Bodies[i].Position, s2.Bodies[i].Position)
+		}
+	}
+}
+
+// 引力、库仑力、弹簧和均匀场都是保守力，总能量应该守恒
+func TestConservativeForcesConserveEnergy(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "A", Mass: 1, Charge: 1, Position: Vec2{-1, 0}, Velocity: Vec2{0, -0.3}},
+		Body{Name: "B", Mass: 2, Charge: -0.5, Position: Vec2{1, 0}, Velocity: Vec2{0, 0.2}},
+		Body{Name: "C", Mass: 0.5, Charge: 0.3, Position: Vec2{0, 1.5}, Velocity: Vec2{0.1, 0}},
+	)
+	s.Forces = []ForceLaw{Newtonian{}, Coulomb{K: 1}, Spring{A: "A", B: "B", K: 3, Length: 1.5}, UniformField{G: Vec2{0, -0.5}}}
+	e0 := s.Energy()
+	integ := &RK4{}
+	for k := 0; k < 10000; k++ {
+		integ.Step(s, 0.0005)
+	}
+	if d := math.Abs(s.Energy()-e0) / math.Abs(e0); d > 1e-8 {
+		t.Errorf("relative energy drift %.3e", d)
+	}
+}
+
+// 阻力只会让能量减少
+func TestDragDissipates(t *testing.T) {
+	p, _ := LookupPreset("figure8")
+	s := p.New()
+	s.Forces = []ForceLaw{Newtonian{}, Drag{Linear: 0.05, Quadratic: 0.05}}
+	integ := &RK4{}
+	prev := s.Energy()
+	for k := 0; k < 100; k++ {
+		for j := 0; j < 10; j++ {
+			integ.Step(s, 0.001)
+		}
+		e := s.Energy()
+		if e >= prev {
+			t.Fatalf("energy rose from %g to %g at t = %g", prev, e, s.Time)
+		}
+		prev = e
+	}
+}
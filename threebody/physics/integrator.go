@@ -0,0 +1,114 @@
+package physics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Integrator 把系统向前推进一个时间步。
+// 测试粒子和有质量天体用同一个格式积分。
+type Integrator interface {
+	Name() string
+	Step(s *System, dt float64)
+}
+
+// NewIntegrator 按名字创建积分器
+func NewIntegrator(name string) (Integrator, error) {
+	f, ok := integrators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown integrator %q (have %v)", name, IntegratorNames())
+	}
+	return f(), nil
+}
+
+// IntegratorNames 返回所有可用积分器的名字
+func IntegratorNames() []string {
+	names := make([]string, 0, len(integrators))
+	for name := range integrators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var integrators = map[string]func() Integrator{
+	"euler":       func() Integrator { return &Euler{} },
+	"verlet":      func() Integrator { return &Verlet{} },
+	"rk4":         func() Integrator { return &RK4{} },
+	"adaptive":    func() Integrator { return &Adaptive{} },
+	"regularized": func() Integrator { return &Regularized{Pair: [2]int{-1, -1}} },
+	"rk4-kahan":   func() Integrator { return &KahanRK4{} },
+	"rk4-big":     func() Integrator { return &BigRK4{} },
+}
+
+// AdvanceTo 用步长 dt 一步步推进 s，直到再走一步就会超过 target，返回走的步数。
+// 步长不整除 target 时剩下的不足一步留到下一次，所以不同步长的系统
+// 反复调用 AdvanceTo 后时间相差不超过各自的一步，不会越差越远
+func AdvanceTo(s *System, integ Integrator, dt, target float64) int {
+	n := 0
+	for s.Time+dt <= target+dt*1e-9 {
+		integ.Step(s, dt)
+		n++
+	}
+	return n
+}
+
+// Euler 是半隐式欧拉法：先更新速度再用新速度更新位置，
+// 和各个 main-*.go 里手写的更新方式相同。一阶精度。
+type Euler struct {
+	pos, vel, acc []Vec2
+}
+
+func (e *Euler) Name() string { return "euler" }
+
+func (e *Euler) Step(s *System, dt float64) {
+	e.pos = s.Positions(e.pos)
+	e.vel = s.Velocities(e.vel)
+	e.acc = grow(e.acc, len(s.Bodies))
+	eulerStep(e.pos, e.vel, e.acc, s.Accelerations, dt)
+	s.setState(e.pos, e.vel)
+	s.Time += dt
+}
+
+// Verlet 是漂移-踢-漂移形式的蛙跳法，二阶辛积分器，
+// 每步只需计算一次加速度，长时间能量误差有界。
+// 速度相关的力（阻力）用步初的速度求值，这部分只有一阶精度。
+type Verlet struct {
+	pos, vel, acc []Vec2
+}
+
+func (v *Verlet) Name() string { return "verlet" }
+
+func (v *Verlet) Step(s *System, dt float64) {
+	v.pos = s.Positions(v.pos)
+	v.vel = s.Velocities(v.vel)
+	v.acc = grow(v.acc, len(s.Bodies))
+	verletStep(v.pos, v.vel, v.acc, s.Accelerations, dt)
+	s.setState(v.pos, v.vel)
+	s.Time += dt
+}
+
+// RK4 是经典四阶龙格-库塔法
+type RK4 struct {
+	rk4Stages[Vec2]
+}
+
+func (r *RK4) Name() string { return "rk4" }
+
+func (r *RK4) Step(s *System, dt float64) {
+	r.slopes(s, dt)
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		b.Position = r.x0[i].Add(r.sumX[i].Mult(dt / 6))
+		b.Velocity = r.v0[i].Add(r.sumV[i].Mult(dt / 6))
+	}
+	s.Time += dt
+}
+
+// slopes 求出这一步四个阶段斜率的加权和 sumX、sumV，
+// 起点状态存在 x0、v0，不修改 s
+func (r *RK4) slopes(s *System, dt float64) {
+	r.x0 = s.Positions(r.x0)
+	r.v0 = s.Velocities(r.v0)
+	r.rk4Stages.slopes(s.Accelerations, dt)
+}
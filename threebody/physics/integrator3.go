@@ -0,0 +1,88 @@
+package physics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Integrator3 把三维系统向前推进一个时间步
+type Integrator3 interface {
+	Name() string
+	Step(s *System3, dt float64)
+}
+
+// NewIntegrator3 按名字创建三维积分器
+func NewIntegrator3(name string) (Integrator3, error) {
+	f, ok := integrators3[name]
+	if !ok {
+		names := make([]string, 0, len(integrators3))
+		for n := range integrators3 {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown 3D integrator %q (have %v)", name, names)
+	}
+	return f(), nil
+}
+
+var integrators3 = map[string]func() Integrator3{
+	"euler":  func() Integrator3 { return &Euler3{} },
+	"verlet": func() Integrator3 { return &Verlet3{} },
+	"rk4":    func() Integrator3 { return &RK4In3D{} },
+}
+
+// accel3 把 System3.Accelerations 包装成积分格式用的形式，三维模式没有速度相关的力
+func accel3(s *System3) accelFunc[Vec3] {
+	return func(pos, _, acc []Vec3) { s.Accelerations(pos, acc) }
+}
+
+// Euler3 是三维的半隐式欧拉法
+type Euler3 struct {
+	pos, vel, acc []Vec3
+}
+
+func (e *Euler3) Name() string { return "euler" }
+
+func (e *Euler3) Step(s *System3, dt float64) {
+	e.pos = s.Positions(e.pos)
+	e.vel = s.Velocities(e.vel)
+	e.acc = grow(e.acc, len(s.Bodies))
+	eulerStep(e.pos, e.vel, e.acc, accel3(s), dt)
+	s.setState(e.pos, e.vel)
+	s.Time += dt
+}
+
+// Verlet3 是三维的漂移-踢-漂移蛙跳法
+type Verlet3 struct {
+	pos, vel, acc []Vec3
+}
+
+func (v *Verlet3) Name() string { return "verlet" }
+
+func (v *Verlet3) Step(s *System3, dt float64) {
+	v.pos = s.Positions(v.pos)
+	v.vel = s.Velocities(v.vel)
+	v.acc = grow(v.acc, len(s.Bodies))
+	verletStep(v.pos, v.vel, v.acc, accel3(s), dt)
+	s.setState(v.pos, v.vel)
+	s.Time += dt
+}
+
+// RK4In3D 是三维的经典四阶龙格-库塔法
+type RK4In3D struct {
+	rk4Stages[Vec3]
+}
+
+func (r *RK4In3D) Name() string { return "rk4" }
+
+func (r *RK4In3D) Step(s *System3, dt float64) {
+	r.x0 = s.Positions(r.x0)
+	r.v0 = s.Velocities(r.v0)
+	r.slopes(accel3(s), dt)
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		b.Position = r.x0[i].Add(r.sumX[i].Mult(dt / 6))
+		b.Velocity = r.v0[i].Add(r.sumV[i].Mult(dt / 6))
+	}
+	s.Time += dt
+}
@@ -0,0 +1,26 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 步长 0.003 不整除每帧的 0.01，按共同的目标时间推进时两个系统的时间差始终小于一步
+func TestAdvanceTo(t *testing.T) {
+	fine, coarse := figureEight(), figureEight()
+	fi, ci := &RK4{}, &Verlet{}
+	target := 0.0
+	for frame := 0; frame < 1000; frame++ {
+		target += 0.01
+		if n := AdvanceTo(fine, fi, 0.003, target); n < 3 || n > 4 {
+			t.Fatalf("frame %d: %d steps of 0.003", frame, n)
+		}
+		AdvanceTo(coarse, ci, 0.01, target)
+		if d := math.Abs(fine.Time - coarse.Time); d >= 0.003 {
+			t.Fatalf("frame %d: times %g and %g differ by %g", frame, fine.Time, coarse.Time, d)
+		}
+	}
+	if math.Abs(coarse.Time-10) > 1e-9 || fine.Time > 10 || fine.Time < 10-0.003 {
+		t.Errorf("after 10 time units: %g and %g", fine.Time, coarse.Time)
+	}
+}
@@ -0,0 +1,130 @@
+package physics
+
+import "math"
+
+// LyapunovSample 是某一时刻的最大李雅普诺夫指数估计
+type LyapunovSample struct {
+	Time     float64
+	Exponent float64
+}
+
+// Lyapunov 用影子轨道（Benettin 方法）估计最大李雅普诺夫指数：
+// 影子轨道从参考轨道出发、在相空间偏离 D0，和参考轨道一起积分，
+// 每隔 Interval 记录一次间距的增长并把间距缩放回 D0。
+type Lyapunov struct {
+	D0       float64
+	Interval float64
+	Bodies   []int // 参与计算间距的天体，默认全部有质量天体
+	Samples  []LyapunovSample
+	// MaxSamples 是 Samples 的上限，为零时取 1000。超过时隔一个丢一个，
+	// 保留整段时间但分辨率减半，最后一个样本总是最新的
+	MaxSamples       int
+	Renormalizations int // 累计的重归一化次数
+
+	shadow *System
+	integ  Integrator
+	start  float64
+	next   float64
+	sumLog float64
+}
+
+// NewLyapunov 在参考系统 ref 的当前状态创建影子轨道，
+// 把第一个被跟踪天体的 x 坐标偏移 d0。影子轨道使用与 integ 同名的积分器，
+// 质量随时间变化时也一样包上 MassVarying。
+func NewLyapunov(ref *System, integ Integrator, d0, interval float64) (*Lyapunov, error) {
+	shadowInteg, err := NewIntegratorLike(integ)
+	if err != nil {
+		return nil, err
+	}
+	l := &Lyapunov{
+		D0:       d0,
+		Interval: interval,
+		Bodies:   append([]int(nil), ref.Massive()...),
+		shadow:   ref.Clone(),
+		integ:    shadowInteg,
+		start:    ref.Time,
+		next:     ref.Time + interval,
+	}
+	if len(l.Bodies) == 0 {
+		l.Bodies = []int{0}
+	}
+	l.shadow.Bodies[l.Bodies[0]].Position.X += d0
+	return l, nil
+}
+
+// Shadow 返回影子轨道
+func (l *Lyapunov) Shadow() *System {
+	return l.shadow
+}
+
+// Separation 返回参考轨道和影子轨道在相空间中的距离
+func (l *Lyapunov) Separation(ref *System) float64 {
+	return PhaseDistance(ref, l.shadow, l.Bodies)
+}
+
+// Advance 把影子轨道推进 dt。调用方负责用同样的 dt 推进参考轨道。
+func (l *Lyapunov) Advance(ref *System, dt float64) {
+	l.integ.Step(l.shadow, dt)
+	l.shadow.WrapPeriodic()
+	if l.shadow.Time < l.next-dt/2 {
+		return
+	}
+	d := l.Separation(ref)
+	if d == 0 {
+		return
+	}
+	l.sumLog += math.Log(d / l.D0)
+	l.Samples = append(l.Samples, LyapunovSample{
+		Time:     ref.Time,
+		Exponent: l.sumLog / (ref.Time - l.start),
+	})
+	l.decimate()
+	l.Renormalizations++
+	l.next += l.Interval
+
+	// 沿当前偏离方向把影子轨道缩放回 D0
+	f := l.D0 / d
+	for _, i := range l.Bodies {
+		r, sh := &ref.Bodies[i], &l.shadow.Bodies[i]
+		sh.Position = r.Position.Add(ref.Displacement(sh.Position, r.Position).Mult(f))
+		sh.Velocity = r.Velocity.Add(sh.Velocity.Sub(r.Velocity).Mult(f))
+	}
+	l.shadow.WrapPeriodic()
+}
+
+// decimate 在样本超过 MaxSamples 时隔一个丢一个
+func (l *Lyapunov) decimate() {
+	limit := l.MaxSamples
+	if limit <= 0 {
+		limit = 1000
+	}
+	if len(l.Samples) <= limit {
+		return
+	}
+	last := l.Samples[len(l.Samples)-1]
+	n := 0
+	for k := 0; k < len(l.Samples)-1; k += 2 {
+		l.Samples[n] = l.Samples[k]
+		n++
+	}
+	l.Samples = append(l.Samples[:n], last)
+}
+
+// Exponent 返回当前的指数估计，还没有样本时为 0
+func (l *Lyapunov) Exponent() float64 {
+	if len(l.Samples) == 0 {
+		return 0
+	}
+	return l.Samples[len(l.Samples)-1].Exponent
+}
+
+// PhaseDistance 返回两个系统中指定天体的相空间距离 √(Σ|Δr|² + |Δv|²)，
+// 周期边界下位置差取最近的镜像（见 System.Displacement）
+func PhaseDistance(a, b *System, bodies []int) float64 {
+	d2 := 0.0
+	for _, i := range bodies {
+		d2 += a.Displacement(a.Bodies[i].Position, b.Bodies[i].Position).Length2()
+		d2 += a.Bodies[i].Velocity.Sub(b.Bodies[i].Velocity).Length2()
+	}
+	return math.Sqrt(d2)
+}
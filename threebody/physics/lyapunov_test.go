@@ -0,0 +1,59 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// runLyapunov 用 RK4 积分 s 到 duration，返回指数估计器
+func runLyapunov(t *testing.T, s *System, duration float64) *Lyapunov {
+	integ := &RK4{}
+	l, err := NewLyapunov(s, integ, 1e-8, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s.Time < duration {
+		integ.Step(s, 0.001)
+		l.Advance(s, 0.001)
+	}
+	return l
+}
+
+func TestLyapunov(t *testing.T) {
+	// 圆轨道是规则运动，偏离只随时间线性增长，指数按 log(t)/t 趋于零
+	circular := runLyapunov(t, keplerOrbit{mu: 1, a: 1, e: 0}.system(0), 200)
+	if x := circular.Exponent(); math.Abs(x) > 0.1 {
+		t.Errorf("circular Kepler orbit: exponent %g, want about 0", x)
+	}
+	// 毕达哥拉斯三体是混沌的
+	p, _ := LookupPreset("pythagorean")
+	chaotic := runLyapunov(t, p.New(), 30)
+	if x := chaotic.Exponent(); x < 0.5 {
+		t.Errorf("pythagorean: exponent %g, want clearly positive", x)
+	}
+}
+
+// 样本超过上限时隔一个丢一个，保留第一个和最新的
+func TestLyapunovSamplesBounded(t *testing.T) {
+	s := keplerOrbit{mu: 1, a: 1, e: 0}.system(0)
+	integ := &Verlet{}
+	l, err := NewLyapunov(s, integ, 1e-8, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.MaxSamples = 100
+	for k := 0; k < 10000; k++ {
+		integ.Step(s, 0.001)
+		l.Advance(s, 0.001)
+	}
+	if n := len(l.Samples); n > 100 || n < 50 {
+		t.Errorf("%d samples with MaxSamples = 100", n)
+	}
+	if l.Renormalizations < 990 {
+		t.Errorf("%d renormalizations, want about 1000", l.Renormalizations)
+	}
+	first, last := l.Samples[0], l.Samples[len(l.Samples)-1]
+	if math.Abs(first.Time-0.01) > 1e-9 || math.Abs(last.Time-s.Time) > 1e-9 || last.Exponent != l.Exponent() {
+		t.Errorf("samples span %g to %g, system at %g", first.Time, last.Time, s.Time)
+	}
+}
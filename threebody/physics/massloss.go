@@ -0,0 +1,174 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MassLaw 给出天体在 t 时刻的质量，m0 是 t = 0 时的质量
+type MassLaw interface {
+	Name() string
+	Mass(m0, t float64) float64
+}
+
+// ConstantMass 是不变的质量
+type ConstantMass struct{}
+
+func (ConstantMass) Name() string               { return "constant" }
+func (ConstantMass) Mass(m0, t float64) float64 { return m0 }
+
+// LinearMassLoss 以固定速率 Rate 损失质量，降到零后变成测试粒子
+type LinearMassLoss struct {
+	Rate float64
+}
+
+func (LinearMassLoss) Name() string { return "linear" }
+
+func (l LinearMassLoss) Mass(m0, t float64) float64 {
+	return math.Max(0, m0-l.Rate*t)
+}
+
+// ExponentialMassLoss 是时间常数为 Tau 的指数衰减
+type ExponentialMassLoss struct {
+	Tau float64
+}
+
+func (ExponentialMassLoss) Name() string { return "exp" }
+
+func (e ExponentialMassLoss) Mass(m0, t float64) float64 {
+	return m0 * math.Exp(-t/e.Tau)
+}
+
+// SuddenMassLoss 在 Time 时刻一次性抛掉 Fraction 比例的质量，比如超新星爆发
+type SuddenMassLoss struct {
+	Time, Fraction float64
+}
+
+func (SuddenMassLoss) Name() string { return "sudden" }
+
+func (s SuddenMassLoss) Mass(m0, t float64) float64 {
+	if t < s.Time {
+		return m0
+	}
+	return m0 * (1 - s.Fraction)
+}
+
+// MassLoss 把一条质量规律绑定到一个天体上
+type MassLoss struct {
+	Body    int     // 天体下标
+	Initial float64 // t = 0 时的质量
+	Law     MassLaw
+}
+
+// SetMassesAt 按 MassLoss 把质量设为 t 时刻的值，速度不变。
+//
+// 各向同性的质量损失在天体自身的静止系里不产生反冲：抛出的物质带走
+// 与其质量成正比的动量，剩下天体的速度保持连续，所以这里只改质量不改速度。
+// 系统的总动量会随之变化，质心也会漂移，这是物质被抛出系统的结果。
+func (s *System) SetMassesAt(t float64) {
+	for _, l := range s.MassLoss {
+		s.Bodies[l.Body].Mass = l.Law.Mass(l.Initial, t)
+	}
+}
+
+// MassVarying 包装一个积分器，让 System.MassLoss 生效：每步积分时质量取步长中点的值，
+// 步末再更新到新时刻的值。突然的质量损失落在哪一步里，就从那一步开始生效。
+type MassVarying struct {
+	Integrator
+}
+
+func (m MassVarying) Step(s *System, dt float64) {
+	if len(s.MassLoss) == 0 {
+		m.Integrator.Step(s, dt)
+		return
+	}
+	s.SetMassesAt(s.Time + dt/2)
+	m.Integrator.Step(s, dt)
+	s.SetMassesAt(s.Time)
+}
+
+// Unwrap 返回被包装的积分器
+func (m MassVarying) Unwrap() Integrator {
+	return m.Integrator
+}
+
+// NewIntegratorLike 新建一个和 integ 同名的积分器，integ 是 MassVarying 时新的也包上一层，
+// 用于需要独立状态的第二份轨道（影子轨道、对照组）
+func NewIntegratorLike(integ Integrator) (Integrator, error) {
+	if m, ok := integ.(MassVarying); ok {
+		inner, err := NewIntegratorLike(m.Integrator)
+		if err != nil {
+			return nil, err
+		}
+		return MassVarying{Integrator: inner}, nil
+	}
+	return NewIntegrator(integ.Name())
+}
+
+// SetMassLoss 解析用分号分隔的质量规律并绑定到天体上，每一项是 "天体:规律:参数"：
+//
+//	A:constant
+//	A:linear:Rate
+//	A:exp:Tau
+//	A:sudden:Time,Fraction
+//
+// 例如 "A:exp:50;C:sudden:20,0.6"。天体按名字查找，当前质量作为 t = 0 时的质量。
+// 空字符串清除已有的规律。
+func (s *System) SetMassLoss(spec string) error {
+	var list []MassLoss
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) < 2 {
+			return fmt.Errorf("mass loss %q: want body:law[:args]", item)
+		}
+		i := s.Index(strings.TrimSpace(parts[0]))
+		if i < 0 {
+			return fmt.Errorf("mass loss %q: no body named %q", item, parts[0])
+		}
+		var args []float64
+		if len(parts) == 3 {
+			for _, a := range strings.Split(parts[2], ",") {
+				x, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+				if err != nil {
+					return fmt.Errorf("mass loss %q: %v", item, err)
+				}
+				args = append(args, x)
+			}
+		}
+		want := map[string]int{"constant": 0, "linear": 1, "exp": 1, "sudden": 2}
+		name := strings.TrimSpace(parts[1])
+		n, ok := want[name]
+		if !ok {
+			return fmt.Errorf("mass loss %q: unknown law %q (have constant, linear, exp, sudden)", item, name)
+		}
+		if len(args) != n {
+			return fmt.Errorf("mass loss %q: %s wants %d arguments", item, name, n)
+		}
+		var law MassLaw
+		switch name {
+		case "constant":
+			law = ConstantMass{}
+		case "linear":
+			law = LinearMassLoss{Rate: args[0]}
+		case "exp":
+			if args[0] <= 0 {
+				return fmt.Errorf("mass loss %q: time constant must be positive", item)
+			}
+			law = ExponentialMassLoss{Tau: args[0]}
+		case "sudden":
+			if args[1] < 0 || args[1] > 1 {
+				return fmt.Errorf("mass loss %q: fraction must be in [0, 1]", item)
+			}
+			law = SuddenMassLoss{Time: args[0], Fraction: args[1]}
+		}
+		list = append(list, MassLoss{Body: i, Initial: s.Bodies[i].Mass, Law: law})
+	}
+	s.MassLoss = list
+	return nil
+}
@@ -0,0 +1,118 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// circularBinary 返回质量为 ma 和 mb、间距为 1 的圆轨道双星
+func circularBinary(ma, mb float64) *System {
+	s := NewSystem(
+		Body{Name: "A", Mass: ma, Position: Vec2{-mb / (ma + mb), 0}},
+		Body{Name: "B", Mass: mb, Position: Vec2{ma / (ma + mb), 0}},
+	)
+	v := math.Sqrt(s.G * (ma + mb))
+	s.Bodies[0].Velocity = Vec2{0, -v * mb / (ma + mb)}
+	s.Bodies[1].Velocity = Vec2{0, v * ma / (ma + mb)}
+	return s
+}
+
+// 圆轨道上突然损失超过一半的总质量会让双星解体，少于一半则仍然束缚
+func TestSuddenMassLoss(t *testing.T) {
+	for _, c := range []struct {
+		spec     string
+		fraction float64
+		unbound  bool
+	}{{"A:sudden:1,0.4", 0.4, false}, {"A:sudden:1,0.9", 0.9, true}} {
+		s := circularBinary(3, 1)
+		if err := s.SetMassLoss(c.spec); err != nil {
+			t.Fatal(err)
+		}
+		integ := MassVarying{&RK4{}}
+		var before, after Vec2
+		for k := 0; k < 5000; k++ {
+			if s.Time < 1 && s.Time+0.01 >= 1 {
+				before = s.Bodies[0].Velocity
+				integ.Step(s, 0.01)
+				after = s.Bodies[0].Velocity
+				continue
+			}
+			integ.Step(s, 0.01)
+		}
+		if want := 3 * (1 - c.fraction); math.Abs(s.Bodies[0].Mass-want) > 1e-12 {
+			t.Errorf("fraction %g: mass %g, want %g", c.fraction, s.Bodies[0].Mass, want)
+		}
+		// 没有反冲：速度在一步之内只按轨道运动变化
+		if d := after.Sub(before).Length(); d > 0.1 {
+			t.Errorf("fraction %g: velocity jumped by %g", c.fraction, d)
+		}
+		e, dist, _ := twoBody(s.G, s.Bodies[0].Mass, s.Bodies[0].Position, s.Bodies[0].Velocity,
+			s.Bodies[1].Mass, s.Bodies[1].Position, s.Bodies[1].Velocity)
+		if (e > 0) != c.unbound || (c.unbound && dist < 20) {
+			t.Errorf("fraction %g: energy %g, separation %g", c.fraction, e, dist)
+		}
+	}
+}
+
+// 缓慢的质量损失下 a·M 是绝热不变量，轨道随质量减小而等比例变宽
+func TestAdiabaticMassLoss(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "star", Mass: 1},
+		Body{Name: "planet", Position: Vec2{1, 0}, Velocity: Vec2{0, 1}},
+	)
+	if err := s.SetMassLoss("star:exp:400"); err != nil {
+		t.Fatal(err)
+	}
+	integ := MassVarying{&RK4{}}
+	for k := 0; k < 20000; k++ {
+		integ.Step(s, 0.01)
+	}
+	m := s.Bodies[0].Mass
+	if want := math.Exp(-0.5); math.Abs(m-want) > 1e-12 {
+		t.Fatalf("mass %g, want %g", m, want)
+	}
+	el := s.OrbitalElements(1, 0)
+	if am := el.SemiMajor * m; math.Abs(am-1) > 0.02 {
+		t.Errorf("a·M = %.4f, want 1 (a = %.4f)", am, el.SemiMajor)
+	}
+}
+
+func TestSetMassLoss(t *testing.T) {
+	s := circularBinary(1, 1)
+	if err := s.SetMassLoss("A:linear:0.1; B:constant"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetMassesAt(20)
+	if s.Bodies[0].Mass != 0 || !s.Bodies[0].IsTest() || s.Bodies[1].Mass != 1 {
+		t.Errorf("masses at t=20: %g, %g", s.Bodies[0].Mass, s.Bodies[1].Mass)
+	}
+	for _, bad := range []string{"X:exp:1", "A:exp", "A:exp:0", "A:sudden:1,2", "A:boil:1", "A"} {
+		if err := s.SetMassLoss(bad); err == nil {
+			t.Errorf("SetMassLoss(%q) should fail", bad)
+		}
+	}
+}
+
+// 影子轨道的质量也要跟着变，否则李雅普诺夫指数量到的是两份系统质量的差别
+func TestLyapunovMassLoss(t *testing.T) {
+	s := circularBinary(1, 1)
+	if err := s.SetMassLoss("A:exp:20"); err != nil {
+		t.Fatal(err)
+	}
+	integ := MassVarying{&RK4{}}
+	l, err := NewLyapunov(s, integ, 1e-8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 4000; k++ {
+		integ.Step(s, 0.01)
+		l.Advance(s, 0.01)
+	}
+	if m := l.Shadow().Bodies[0].Mass; math.Abs(m-s.Bodies[0].Mass) > 1e-12 {
+		t.Errorf("shadow mass %g, reference mass %g", m, s.Bodies[0].Mass)
+	}
+	// 双星的运动是规则的，指数只有 log(t)/t 量级
+	if x := l.Exponent(); x > 0.5 {
+		t.Errorf("exponent %g for a regular orbit", x)
+	}
+}
@@ -0,0 +1,49 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// AddDisk 加入 n 个测试粒子组成的盘，粒子在 [rMin, rMax] 内面密度均匀分布，
+// 以圆轨道速度逆时针绕 center 号天体运动；center < 0 时绕全部有质量天体的质心。
+// 相同的 seed 生成相同的盘。返回第一个粒子的下标。
+func (s *System) AddDisk(center, n int, rMin, rMax float64, seed int64) int {
+	var (
+		origin, drift Vec2
+		mass          float64
+	)
+	if center >= 0 {
+		c := &s.Bodies[center]
+		origin, drift, mass = c.Position, c.Velocity, c.Mass
+	} else {
+		origin, drift = s.CenterOfMass()
+		mass = s.TotalMass()
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	first := len(s.Bodies)
+	for k := 0; k < n; k++ {
+		// 面密度均匀：r² 在 [rMin², rMax²] 上均匀分布
+		r := math.Sqrt(rMin*rMin + rng.Float64()*(rMax*rMax-rMin*rMin))
+		th := rng.Float64() * 2 * math.Pi
+		dir := Vec2{math.Cos(th), math.Sin(th)}
+		v := math.Sqrt(s.G * mass / r)
+		s.Bodies = append(s.Bodies, Body{
+			Position: origin.Add(dir.Mult(r)),
+			Velocity: drift.Add(Vec2{-dir.Y, dir.X}.Mult(v)),
+		})
+	}
+	return first
+}
+
+// TestParticles 返回测试粒子的个数
+func (s *System) TestParticles() int {
+	n := 0
+	for i := range s.Bodies {
+		if s.Bodies[i].IsTest() {
+			n++
+		}
+	}
+	return n
+}
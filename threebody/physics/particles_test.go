@@ -0,0 +1,45 @@
+package physics
+
+import "testing"
+
+func TestTestParticlesDoNotPerturbMassiveBodies(t *testing.T) {
+	plain := figureEight()
+	withDisk := figureEight()
+	withDisk.AddDisk(-1, 500, 1.5, 3, 1)
+	// rk4-big 全用 big.Float 运算，500 个粒子要跑好几秒，给它一个小盘
+	smallDisk := figureEight()
+	smallDisk.AddDisk(-1, 10, 1.5, 3, 1)
+
+	for _, name := range IntegratorNames() {
+		a, b := plain.Clone(), withDisk.Clone()
+		if name == "rk4-big" {
+			b = smallDisk.Clone()
+		}
+		ia, _ := NewIntegrator(name)
+		ib, _ := NewIntegrator(name)
+		for step := 0; step < 200; step++ {
+			ia.Step(a, 0.01)
+			ib.Step(b, 0.01)
+		}
+		for i := range a.Bodies {
+			if a.Bodies[i].Position != b.Bodies[i].Position {
+				t.Errorf("%s: body %d moved differently with test particles: %v vs %v",
+					name, i, a.Bodies[i].Position, b.Bodies[i].Position)
+			}
+		}
+	}
+}
+
+func TestAddDiskIsDeterministic(t *testing.T) {
+	a, b := figureEight(), figureEight()
+	a.AddDisk(0, 100, 0.1, 0.2, 42)
+	b.AddDisk(0, 100, 0.1, 0.2, 42)
+	for i := range a.Bodies {
+		if a.Bodies[i] != b.Bodies[i] {
+			t.Fatalf("particle %d differs for the same seed", i)
+		}
+	}
+	if n := a.TestParticles(); n != 100 {
+		t.Errorf("TestParticles() = %d, want 100", n)
+	}
+}
@@ -0,0 +1,121 @@
+package physics
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	a := []complex128{1, 2i, -3, 4 + 1i, 0.5, -1, 2, 7i}
+	got := append([]complex128(nil), a...)
+	fft(got, false)
+	for k := range a {
+		var want complex128
+		for j, x := range a {
+			want += x * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(len(a)))
+		}
+		if cmplx.Abs(got[k]-want) > 1e-12 {
+			t.Errorf("X[%d] = %v, want %v", k, got[k], want)
+		}
+	}
+	fft(got, true)
+	for k := range a {
+		if cmplx.Abs(got[k]-a[k]) > 1e-12 {
+			t.Errorf("round trip [%d] = %v, want %v", k, got[k], a[k])
+		}
+	}
+}
+
+// 盒子比星团大得多时周期镜像可以忽略；软化取 4 个网格间距时，
+// 网格力和相同软化的直接求和相差约百分之一
+func TestParticleMeshAgainstDirectSum(t *testing.T) {
+	s := ColdCollapse(400, 1)
+	pm := &ParticleMesh{Box: 8, Grid: 256}
+	s.Softening = 4 * pm.spacing()
+
+	pos, vel := s.Positions(nil), s.Velocities(nil)
+	direct := make([]Vec2, len(pos))
+	s.Accelerations(pos, vel, direct)
+	s.Forces = []ForceLaw{pm}
+	mesh := make([]Vec2, len(pos))
+	s.Accelerations(pos, vel, mesh)
+
+	errs := make([]float64, len(pos))
+	for i := range pos {
+		errs[i] = mesh[i].Sub(direct[i]).Length() / direct[i].Length()
+	}
+	sort.Float64s(errs)
+	if median, p90 := errs[len(errs)/2], errs[len(errs)*9/10]; median > 0.015 || p90 > 0.04 {
+		t.Errorf("relative force error: median %.3f, 90th percentile %.3f", median, p90)
+	}
+
+	// 动量守恒：网格力的合力接近零
+	var total Vec2
+	for i := range mesh {
+		total = total.Add(mesh[i].Mult(s.Bodies[i].Mass))
+	}
+	if total.Length() > 1e-3 {
+		t.Errorf("net force %v", total)
+	}
+}
+
+func TestParticleMeshPeriodic(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "a", Mass: 1, Position: Vec2{-1, 0}},
+		Body{Name: "b", Mass: 1, Position: Vec2{1, 0}},
+	)
+	pm := &ParticleMesh{Box: 4, Grid: 64}
+	s.Forces = []ForceLaw{pm}
+	// 在边长 4 的周期盒子里，相距 2 的两颗星从两边互相吸引，合力为零
+	acc := make([]Vec2, 2)
+	s.Accelerations(s.Positions(nil), s.Velocities(nil), acc)
+	if acc[0].Length() > 1e-9 {
+		t.Errorf("acceleration at half a box %v, want 0", acc[0])
+	}
+
+	s.Bodies[0].Position = Vec2{2.5, -3}
+	pm.Wrap(s)
+	if p := s.Bodies[0].Position; math.Abs(p.X+1.5) > 1e-12 || math.Abs(p.Y-1) > 1e-12 {
+		t.Errorf("wrapped to %v, want (-1.5, 1)", p)
+	}
+
+	if _, err := ParseForces("pm:4,48"); err == nil {
+		t.Errorf("grid 48 should be rejected")
+	}
+	laws, err := ParseForces("pm:4,64")
+	if err != nil || laws[0].(*ParticleMesh).Grid != 64 {
+		t.Errorf("ParseForces(pm:4,64) = %v, %v", laws, err)
+	}
+}
+
+// 天体穿过盒子边界时，参考轨道和影子轨道可能在不同的步折回，
+// 距离要按最近镜像算，否则指数会被一个盒子长度的跳变污染
+func TestParticleMeshLyapunov(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "a", Mass: 1, Position: Vec2{1.9, 0}, Velocity: Vec2{1, 0}},
+		Body{Name: "b", Mass: 1, Position: Vec2{-1, 0.5}},
+	)
+	s.Softening = 0.5
+	s.Forces = []ForceLaw{&ParticleMesh{Box: 4, Grid: 32}}
+	integ := &Verlet{}
+	l, err := NewLyapunov(s, integ, 1e-6, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossed := false
+	for k := 0; k < 300; k++ {
+		integ.Step(s, 0.01)
+		if s.WrapPeriodic() {
+			crossed = true
+		}
+		l.Advance(s, 0.01)
+	}
+	if !crossed {
+		t.Fatal("no body crossed the box edge")
+	}
+	if x := l.Exponent(); x > 2 {
+		t.Errorf("exponent %g after crossing the box edge", x)
+	}
+}
@@ -0,0 +1,117 @@
+package physics
+
+import "math"
+
+// PostNewtonian 是一阶后牛顿（1PN）Einstein-Infeld-Hoffmann 修正项，
+// 只包含修正部分，要和 Newtonian 一起使用。C 是模拟单位下的光速，
+// 越小相对论效应越明显。其他天体的加速度取牛顿近似。
+//
+// 这里的修正依赖速度，不是 Conservative：Energy 仍按牛顿能量计算，
+// 会有 v²/c² 量级的周期起伏。修正项不做软化。
+type PostNewtonian struct {
+	C float64
+}
+
+func (PostNewtonian) Name() string { return "1pn" }
+
+func (pn PostNewtonian) Accumulate(s *System, pos, vel, acc []Vec2) {
+	src := s.Massive()
+	c2 := pn.C * pn.C
+
+	// 引力源的牛顿加速度和牛顿势 Σ G·m/r，按 src 的顺序存放
+	newton := make([]Vec2, len(src))
+	phi := make([]float64, len(src))
+	for k, b := range src {
+		for _, c := range src {
+			if c == b {
+				continue
+			}
+			d := pos[c].Sub(pos[b])
+			r := d.Length()
+			newton[k] = newton[k].Add(d.Mult(s.G * s.Bodies[c].Mass / (r * r * r)))
+			phi[k] += s.G * s.Bodies[c].Mass / r
+		}
+	}
+
+	for i := range pos {
+		xi, vi := pos[i], vel[i]
+		phiI := 0.0
+		for _, c := range src {
+			if c != i {
+				phiI += s.G * s.Bodies[c].Mass / pos[c].Sub(xi).Length()
+			}
+		}
+		var a Vec2
+		for k, j := range src {
+			if j == i {
+				continue
+			}
+			xj, vj, aj := pos[j], vel[j], newton[k]
+			d := xj.Sub(xi) // 从 i 指向 j
+			r := d.Length()
+			gm := s.G * s.Bodies[j].Mass
+			nv := d.Dot(vj) / r
+
+			// 牛顿项的修正因子（去掉了 1，牛顿部分由 Newtonian 负责）
+			f := -4*phiI - phi[k] + vi.Length2() + 2*vj.Length2() - 4*vi.Dot(vj) - 1.5*nv*nv + 0.5*d.Dot(aj)
+			a = a.Add(d.Mult(gm / (r * r * r) * f))
+			// 速度项 [(x_i - x_j)·(4v_i - 3v_j)] (v_i - v_j)
+			a = a.Add(vi.Sub(vj).Mult(gm / (r * r * r) * xi.Sub(xj).Dot(vi.Mult(4).Sub(vj.Mult(3)))))
+			// 其他天体加速度带来的项
+			a = a.Add(aj.Mult(3.5 * gm / r))
+		}
+		acc[i] = acc[i].Add(a.Mult(1 / c2))
+	}
+}
+
+// EccentricityVector 返回 i 号天体相对 j 号天体的开普勒偏心率矢量，
+// 指向近心点，长度是偏心率
+func (s *System) EccentricityVector(i, j int) Vec2 {
+	r := s.Bodies[i].Position.Sub(s.Bodies[j].Position)
+	v := s.Bodies[i].Velocity.Sub(s.Bodies[j].Velocity)
+	mu := s.G * (s.Bodies[i].Mass + s.Bodies[j].Mass)
+	return r.Mult(v.Length2()/mu - 1/r.Length()).Sub(v.Mult(r.Dot(v) / mu))
+}
+
+// Pericenters 记录 I 号天体相对 J 号天体每次过近心点的时刻和近心点方向，
+// 每步之后调用 Update，用来测量近心点进动
+type Pericenters struct {
+	I, J   int
+	Times  []float64
+	Angles []float64 // 近心点方向角，已展开成连续值
+
+	prev    float64 // 上一次的 r·v
+	started bool
+}
+
+// Update 在 r·v 由负变正（距离由减小变为增大）时记录一次近心点
+func (p *Pericenters) Update(s *System) {
+	r := s.Bodies[p.I].Position.Sub(s.Bodies[p.J].Position)
+	v := s.Bodies[p.I].Velocity.Sub(s.Bodies[p.J].Velocity)
+	rv := r.Dot(v)
+	if p.started && p.prev < 0 && rv >= 0 {
+		e := s.EccentricityVector(p.I, p.J)
+		angle := math.Atan2(e.Y, e.X)
+		if n := len(p.Angles); n > 0 {
+			// 展开到与上一次最接近的分支
+			angle += 2 * math.Pi * math.Round((p.Angles[n-1]-angle)/(2*math.Pi))
+		}
+		p.Times = append(p.Times, s.Time)
+		p.Angles = append(p.Angles, angle)
+	}
+	p.prev, p.started = rv, true
+}
+
+// PerOrbit 返回平均每圈的近心点进动角（弧度），少于两次近心点时返回 0
+func (p *Pericenters) PerOrbit() float64 {
+	n := len(p.Angles)
+	if n < 2 {
+		return 0
+	}
+	return (p.Angles[n-1] - p.Angles[0]) / float64(n-1)
+}
+
+// PrecessionGR 返回广义相对论给出的每圈近心点进动 6πGM/(c²a(1-e²))
+func PrecessionGR(g, m, c, a, e float64) float64 {
+	return 6 * math.Pi * g * m / (c * c * a * (1 - e*e))
+}
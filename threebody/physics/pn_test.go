@@ -0,0 +1,39 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 1PN 修正下的近心点进动应接近 6πGM/(c²a(1-e²))，纯牛顿时没有进动
+func TestPerihelionPrecession(t *testing.T) {
+	const c = 30
+	for _, pn := range []bool{true, false} {
+		p, _ := LookupPreset("mercury")
+		s := p.New()
+		s.Forces = []ForceLaw{Newtonian{}}
+		if pn {
+			s.Forces = append(s.Forces, PostNewtonian{C: c})
+		}
+		integ := &RK4{}
+		tr := Pericenters{I: s.Index("mercury"), J: s.Index("sun")}
+		for k := 0; k < 40000; k++ {
+			integ.Step(s, 0.001)
+			tr.Update(s)
+		}
+		if len(tr.Angles) < 5 {
+			t.Fatalf("only %d pericenter passages", len(tr.Angles))
+		}
+		got := tr.PerOrbit()
+		if !pn {
+			if math.Abs(got) > 1e-9 {
+				t.Errorf("Newtonian orbit precesses by %.3e rad per orbit", got)
+			}
+			continue
+		}
+		want := PrecessionGR(s.G, s.TotalMass(), c, 1, MercuryEccentricity)
+		if math.Abs(got/want-1) > 0.01 {
+			t.Errorf("1PN precession %.6e rad per orbit, want %.6e", got, want)
+		}
+	}
+}
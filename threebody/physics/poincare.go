@@ -0,0 +1,114 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SectionPoint 是轨道穿过截面的一个点，Q、P 是另一个坐标和对应的速度分量
+type SectionPoint struct {
+	Time float64
+	Q, P float64
+}
+
+// Section 是庞加莱截面 {Axis = Value}，只记录沿 Direction 方向穿过的点。
+// 比如 y = 0、vy > 0 的截面记录每次穿越时的 (x, vx)。
+// 每步之后调用 Update，步内用三次 Hermite 插值求出穿越时刻。
+type Section struct {
+	Axis      byte // 'x' 或 'y'
+	Value     float64
+	Direction int // +1 只记录速度分量为正的穿越，-1 只记录为负的，0 都记录
+	Points    []SectionPoint
+
+	time     float64
+	pos, vel Vec2
+	started  bool
+}
+
+// ParseSection 解析 "y=0,vy>0" 形式的截面定义，方向部分可以省略
+func ParseSection(spec string) (*Section, error) {
+	plane, dir, hasDir := strings.Cut(strings.ReplaceAll(spec, " ", ""), ",")
+	axis, value, ok := strings.Cut(plane, "=")
+	if !ok || (axis != "x" && axis != "y") {
+		return nil, fmt.Errorf("section %q: want x=<value> or y=<value>", spec)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("section %q: %v", spec, err)
+	}
+	sec := &Section{Axis: axis[0], Value: v}
+	if hasDir {
+		switch dir {
+		case "v" + axis + ">0":
+			sec.Direction = 1
+		case "v" + axis + "<0":
+			sec.Direction = -1
+		default:
+			return nil, fmt.Errorf("section %q: direction must be v%s>0 or v%s<0", spec, axis, axis)
+		}
+	}
+	return sec, nil
+}
+
+// String 返回截面定义，格式与 ParseSection 相同
+func (sec *Section) String() string {
+	s := fmt.Sprintf("%c=%g", sec.Axis, sec.Value)
+	switch sec.Direction {
+	case 1:
+		s += fmt.Sprintf(",v%c>0", sec.Axis)
+	case -1:
+		s += fmt.Sprintf(",v%c<0", sec.Axis)
+	}
+	return s
+}
+
+// split 把向量拆成截面法向分量和截面内分量
+func (sec *Section) split(v Vec2) (normal, along float64) {
+	if sec.Axis == 'x' {
+		return v.X, v.Y
+	}
+	return v.Y, v.X
+}
+
+// Update 用天体在时刻 t 的状态检查上一步以来是否穿过截面，穿过时记录并返回 true
+func (sec *Section) Update(t float64, b *Body) bool {
+	defer func() { sec.time, sec.pos, sec.vel, sec.started = t, b.Position, b.Velocity, true }()
+	if !sec.started || t <= sec.time {
+		return false
+	}
+	n0, _ := sec.split(sec.pos)
+	n1, _ := sec.split(b.Position)
+	g0, g1 := n0-sec.Value, n1-sec.Value
+	up := g0 < 0 && g1 >= 0
+	down := g0 > 0 && g1 <= 0
+	if !(up && sec.Direction >= 0 || down && sec.Direction <= 0) {
+		return false
+	}
+
+	seg := hermite{r0: sec.pos, v0: sec.vel, r1: b.Position, v1: b.Velocity, h: t - sec.time}
+	theta := bisect(func(theta float64) float64 {
+		r, _ := seg.at(theta)
+		n, _ := sec.split(r)
+		return n - sec.Value
+	}, g0)
+	r, v := seg.at(theta)
+	_, q := sec.split(r)
+	_, p := sec.split(v)
+	sec.Points = append(sec.Points, SectionPoint{Time: sec.time + theta*seg.h, Q: q, P: p})
+	return true
+}
+
+// AddOnSection 在 x 轴上 (x, 0) 处放一个 vx = 0、vy > 0 的粒子，
+// vy 由雅可比常数 cj 决定。该点位于禁区内（2Ω < cj）时返回 false。
+// 用同一个 cj 放一排粒子，它们的 y = 0 截面就拼成一张完整的庞加莱图。
+func (r *Restricted) AddOnSection(x, cj float64) bool {
+	p := Vec2{x, 0}
+	v2 := 2*r.EffectivePotential(p) - cj
+	if v2 < 0 || math.IsInf(v2, 0) {
+		return false
+	}
+	r.Particles = append(r.Particles, Body{Position: p, Velocity: Vec2{0, math.Sqrt(v2)}})
+	return true
+}
@@ -0,0 +1,91 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSection(t *testing.T) {
+	for _, spec := range []string{"y=0,vy>0", "x=0.5,vx<0", "y=-1"} {
+		sec, err := ParseSection(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sec.String(); got != spec {
+			t.Errorf("ParseSection(%q).String() = %q", spec, got)
+		}
+	}
+	for _, bad := range []string{"z=0", "y", "y=0,vx>0"} {
+		if _, err := ParseSection(bad); err == nil {
+			t.Errorf("ParseSection(%q) succeeded", bad)
+		}
+	}
+}
+
+// 圆轨道每圈向上穿过 y = 0 一次，穿越点在 (x, vx) = (r, 0)
+func TestSectionCircularOrbit(t *testing.T) {
+	s := NewSystem(
+		Body{Name: "sun", Mass: 1},
+		Body{Name: "planet", Position: Vec2{0, -1}, Velocity: Vec2{1, 0}},
+	)
+	sec, _ := ParseSection("y=0,vy>0")
+	integ := &RK4{}
+	for k := 0; k < 1200; k++ {
+		integ.Step(s, 0.05)
+		sec.Update(s.Time, &s.Bodies[1])
+	}
+	if len(sec.Points) != 10 {
+		t.Fatalf("got %d crossings in %.1f, want 10", len(sec.Points), s.Time)
+	}
+	for k, p := range sec.Points {
+		want := math.Pi/2 + 2*math.Pi*float64(k)
+		if math.Abs(p.Time-want) > 1e-4 || math.Abs(p.Q-1) > 1e-4 || math.Abs(p.P) > 1e-4 {
+			t.Errorf("crossing %d = %+v, want t=%.5f q=1 p=0", k, p, want)
+		}
+	}
+}
+
+// 限制性问题里的截面点应满足同一个雅可比常数
+func TestSectionRestrictedJacobi(t *testing.T) {
+	const cj = 3.1
+	r := NewRestricted(0.01)
+	if !r.AddOnSection(0.5, cj) {
+		t.Fatal("x = 0.5 is forbidden")
+	}
+	if r.AddOnSection(0.9, 10) {
+		t.Error("point inside the forbidden region accepted")
+	}
+	sec, _ := ParseSection("y=0,vy>0")
+	for k := 0; k < 20000; k++ {
+		r.Step(0.005)
+		sec.Update(r.Time, &r.Particles[0])
+	}
+	if len(sec.Points) < 5 {
+		t.Fatalf("only %d crossings", len(sec.Points))
+	}
+	for _, p := range sec.Points {
+		// 截面上 y = 0，vy 由 C 和 (x, vx) 定出，必须是实数
+		if vy2 := 2*r.EffectivePotential(Vec2{p.Q, 0}) - cj - p.P*p.P; vy2 < -1e-6 {
+			t.Errorf("crossing %+v violates the Jacobi constant (vy² = %g)", p, vy2)
+		}
+	}
+}
+
+// 蝌蚪形轨道在 L4 附近摆动，不穿过 y = 0，但会反复穿过过 L4 的竖线
+func TestSectionTadpole(t *testing.T) {
+	r := NewRestricted(0.001)
+	r.AddCoorbital(1.005, 70*math.Pi/180)
+	horizontal, _ := ParseSection("y=0,vy>0")
+	throughL4, _ := ParseSection("x=0.499,vx<0")
+	for r.Time < 1000 {
+		r.Step(0.005)
+		horizontal.Update(r.Time, &r.Particles[0])
+		throughL4.Update(r.Time, &r.Particles[0])
+	}
+	if n := len(horizontal.Points); n != 0 {
+		t.Errorf("tadpole crossed y=0 %d times", n)
+	}
+	if n := len(throughL4.Points); n < 5 {
+		t.Errorf("tadpole crossed the line through L4 only %d times", n)
+	}
+}
@@ -0,0 +1,159 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Preset 是一个内置的初始条件
+type Preset struct {
+	Name        string
+	Description string
+	New         func() *System
+}
+
+var presets = []Preset{
+	{"figure8", "Chenciner-Montgomery figure-eight orbit", figureEight},
+	{"lagrange", "equilateral Lagrange triangle in rigid rotation", lagrangeTriangle},
+	{"pythagorean", "Burrau's 3-4-5 problem starting at rest", pythagorean},
+	{"hierarchical", "tight binary with a distant third star", hierarchical},
+	{"trisolaris", "three suns and a planet orbiting one of them", trisolaris},
+	{"mercury", "eccentric orbit with the 1PN correction (c = 10) showing perihelion precession", mercury},
+	{"galaxies", "two disk galaxies of test-particle stars on a parabolic encounter (tidal tails)", galaxies},
+}
+
+// Presets 返回所有内置场景
+func Presets() []Preset {
+	return presets
+}
+
+// LookupPreset 按名字查找内置场景
+func LookupPreset(name string) (Preset, error) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q (have %v)", name, names)
+}
+
+func figureEight() *System {
+	v := Vec2{-0.93240737, -0.86473146}
+	return NewSystem(
+		Body{Name: "A", Mass: 1, Radius: 0.03, Position: Vec2{0.97000436, -0.24308753}, Velocity: v.Mult(-0.5)},
+		Body{Name: "B", Mass: 1, Radius: 0.03, Position: Vec2{-0.97000436, 0.24308753}, Velocity: v.Mult(-0.5)},
+		Body{Name: "C", Mass: 1, Radius: 0.03, Position: Vec2{0, 0}, Velocity: v},
+	)
+}
+
+func lagrangeTriangle() *System {
+	// 边长为 1 的等边三角形，每个天体到质心距离 r = 1/√3，
+	// 圆周运动角速度满足 ω² = G·M/1³（M 为总质量）
+	const m = 1.0
+	r := 1 / math.Sqrt(3)
+	omega := math.Sqrt(3 * m)
+	s := NewSystem()
+	for k, name := range []string{"A", "B", "C"} {
+		th := 2 * math.Pi * float64(k) / 3
+		dir := Vec2{math.Cos(th), math.Sin(th)}
+		s.Bodies = append(s.Bodies, Body{
+			Name:     name,
+			Mass:     m,
+			Radius:   0.03,
+			Position: dir.Mult(r),
+			Velocity: Vec2{-dir.Y, dir.X}.Mult(omega * r),
+		})
+	}
+	return s
+}
+
+func pythagorean() *System {
+	s := NewSystem(
+		Body{Name: "3", Mass: 3, Radius: 0.05, Position: Vec2{1, 3}},
+		Body{Name: "4", Mass: 4, Radius: 0.05, Position: Vec2{-2, -1}},
+		Body{Name: "5", Mass: 5, Radius: 0.05, Position: Vec2{1, -1}},
+	)
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+func hierarchical() *System {
+	s := NewSystem(
+		Body{Name: "A", Mass: 1, Radius: 0.03, Position: Vec2{-0.25, 0}},
+		Body{Name: "B", Mass: 1, Radius: 0.03, Position: Vec2{0.25, 0}},
+		Body{Name: "C", Mass: 0.5, Radius: 0.03, Position: Vec2{3, 0}},
+	)
+	// 内双星相互绕转
+	vb := math.Sqrt(s.G*2/0.5) / 2
+	s.Bodies[0].Velocity = Vec2{0, -vb}
+	s.Bodies[1].Velocity = Vec2{0, vb}
+	// 第三颗星绕内双星质心做近似圆周运动
+	s.Bodies[2].Velocity = Vec2{0, math.Sqrt(s.G * 2 / 3)}
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+func trisolaris() *System {
+	s := NewSystem(
+		Body{Name: "A", Mass: 0.82, Radius: 0.05, Position: Vec2{2.86, -2.58}, Velocity: Vec2{0.096, 0.027}},
+		Body{Name: "B", Mass: 1.19, Radius: 0.05, Position: Vec2{-2.83, -1.09}, Velocity: Vec2{-0.072, -0.225}},
+		Body{Name: "C", Mass: 1.09, Radius: 0.05, Position: Vec2{0.93, 3.15}, Velocity: Vec2{0.006, 0.225}},
+	)
+	s.ToCenterOfMassFrame()
+	// 行星绕 A 运行；其余两颗太阳靠近时进入乱纪元
+	s.AddPlanet(0, 0.15, 1)
+	return s
+}
+
+// 水星轨道的偏心率；半长轴取 1
+const (
+	MercuryEccentricity = 0.2056
+	MercuryLightSpeed   = 10.0
+)
+
+func mercury() *System {
+	const e = MercuryEccentricity
+	s := NewSystem(
+		Body{Name: "sun", Mass: 1, Radius: 0.05},
+		Body{Name: "mercury", Mass: 1e-7, Radius: 0.015},
+	)
+	// 从近日点出发，半长轴 1
+	rp := 1 - e
+	s.Bodies[1].Position = Vec2{rp, 0}
+	s.Bodies[1].Velocity = Vec2{0, math.Sqrt(s.G * s.TotalMass() * (1 + e) / rp)}
+	s.ToCenterOfMassFrame()
+	// 真实水星的 v/c 约 1.6e-4，每圈只进动 0.1 角秒；
+	// 这里把光速调小到 10，每圈进动约 0.2 弧度，几圈就能看出来
+	s.Forces = []ForceLaw{Newtonian{}, PostNewtonian{C: MercuryLightSpeed}}
+	return s
+}
+
+// 星系碰撞场景的参数
+const (
+	GalaxyStars      = 3000 // 每个星系盘的测试粒子数
+	GalaxyPericenter = 1.0  // 两个核心抛物线轨道的近心距
+)
+
+func galaxies() *System {
+	s := NewSystem(
+		Body{Name: "core A", Mass: 1, Radius: 0.04},
+		Body{Name: "core B", Mass: 1, Radius: 0.04},
+	)
+	// 两个核心的相对运动是近心距为 q 的抛物线，从相距 6 处、近心点之前出发，
+	// 绕向为逆时针，和两个盘的转向相同（顺行交会，潮汐尾最明显）
+	const q, r0 = GalaxyPericenter, 6.0
+	mu := s.G * s.TotalMass()
+	f := -math.Acos(2*q/r0 - 1)
+	rel := Vec2{math.Cos(f), math.Sin(f)}.Mult(r0)
+	relV := Vec2{-math.Sin(f), 1 + math.Cos(f)}.Mult(math.Sqrt(mu / (2 * q)))
+	s.Bodies[0].Position, s.Bodies[0].Velocity = rel.Mult(-0.5), relV.Mult(-0.5)
+	s.Bodies[1].Position, s.Bodies[1].Velocity = rel.Mult(0.5), relV.Mult(0.5)
+	// 盘的半径取近心距的 0.6 倍，交会时外圈受到的潮汐力最强
+	s.AddDisk(0, GalaxyStars, 0.1, 0.6*q, 1)
+	s.AddDisk(1, GalaxyStars, 0.1, 0.6*q, 2)
+	return s
+}
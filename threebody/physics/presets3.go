@@ -0,0 +1,71 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Preset3 是一个三维初始条件
+type Preset3 struct {
+	Name        string
+	Description string
+	New         func() *System3
+}
+
+var presets3 = []Preset3{
+	{"kozai", "planet with a distant companion inclined by 65 degrees (Kozai-Lidov cycles)", kozai},
+	{"tilted-figure8", "figure-eight with one body kicked out of the plane", tiltedFigureEight},
+}
+
+// Presets3 返回所有三维场景
+func Presets3() []Preset3 {
+	return presets3
+}
+
+// LookupPreset3 按名字查找三维场景；找不到时把同名的二维场景
+// 放进三维空间并倾斜 30°。三维模式只有牛顿引力，带其他力的二维场景（比如 mercury 的 1PN）报错
+func LookupPreset3(name string) (Preset3, error) {
+	for _, p := range presets3 {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	p, err := LookupPreset(name)
+	if err != nil {
+		return Preset3{}, err
+	}
+	if err := Liftable(p.New()); err != nil {
+		return Preset3{}, fmt.Errorf("preset %s: %v", p.Name, err)
+	}
+	return Preset3{p.Name, p.Description + " (tilted into 3D)", func() *System3 {
+		return Lift(p.New(), math.Pi/6)
+	}}, nil
+}
+
+func kozai() *System3 {
+	const incl = 65 * math.Pi / 180
+	s := NewSystem3(
+		Body3{Name: "star", Mass: 1, Radius: 0.05},
+		Body3{Name: "planet", Mass: 1e-3, Radius: 0.02},
+		Body3{Name: "companion", Mass: 1, Radius: 0.05},
+	)
+	// 行星在 xy 平面上绕恒星做近圆轨道，a = 1、e = 0.05，从远心点出发
+	const e = 0.05
+	s.Bodies[1].Position = Vec3{1 + e, 0, 0}
+	s.Bodies[1].Velocity = Vec3{0, math.Sqrt(s.G * 1.001 * (1 - e) / (1 + e)), 0}
+	// 伴星绕内双星质心做半径 5 的圆轨道，轨道面倾斜 incl
+	const aOut = 5.0
+	inner := 1.001
+	vOut := math.Sqrt(s.G * (inner + 1) / aOut)
+	s.Bodies[2].Position = Vec3{0, aOut, 0}.RotateX(incl)
+	s.Bodies[2].Velocity = Vec3{-vOut, 0, 0}
+	s.ToCenterOfMassFrame()
+	return s
+}
+
+func tiltedFigureEight() *System3 {
+	s := Lift(figureEight(), 0)
+	s.Bodies[2].Velocity.Z = 0.1
+	s.ToCenterOfMassFrame()
+	return s
+}
@@ -0,0 +1,38 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 两个核心沿抛物线在近心距处交会，交会后外圈的星被潮汐力拉出盘外
+func TestGalaxies(t *testing.T) {
+	p, _ := LookupPreset("galaxies")
+	s := p.New()
+	if n := s.TestParticles(); n != 2*GalaxyStars {
+		t.Fatalf("%d stars, want %d", n, 2*GalaxyStars)
+	}
+	if e := s.Energy(); math.Abs(e) > 1e-12 {
+		t.Errorf("core orbit energy %g, want 0 (parabolic)", e)
+	}
+	integ := &Verlet{}
+	closest := math.Inf(1)
+	for k := 0; k < 1800; k++ {
+		integ.Step(s, 0.005)
+		closest = math.Min(closest, s.Bodies[1].Position.Sub(s.Bodies[0].Position).Length())
+	}
+	if math.Abs(closest-GalaxyPericenter) > 1e-3 {
+		t.Errorf("closest approach %.4f, want %g", closest, GalaxyPericenter)
+	}
+	// 一开始所有的星都在核心 0.6 以内；交会后数一数离两个核心都超过 2 的
+	tail := 0
+	for i := 2; i < len(s.Bodies); i++ {
+		b := s.Bodies[i].Position
+		if b.Sub(s.Bodies[0].Position).Length() > 2 && b.Sub(s.Bodies[1].Position).Length() > 2 {
+			tail++
+		}
+	}
+	if frac := float64(tail) / float64(2*GalaxyStars); frac < 0.1 {
+		t.Errorf("only %.1f%% of the stars in tidal tails", 100*frac)
+	}
+}
@@ -0,0 +1,44 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// TripleParams 控制随机三体初始条件的分布
+type TripleParams struct {
+	MassMin, MassMax float64 // 质量在此区间均匀分布
+	Virial           float64 // 维里比 Q = T/|W|，0 为冷启动，0.5 为维里平衡
+	Spin             float64 // 角动量参数，取 [-1, 1]：0 为各向同性速度，±1 为纯切向（逆/顺时针）
+}
+
+// RandomTriple 生成一个随机三体系统：位置在单位圆盘内均匀分布，
+// 速度方向随机并按 Spin 混入切向分量，再按维里比缩放。
+// 最后换到质心系，并在束缚时把尺度归一化到 E = -1/4（Hénon 单位），
+// 这样不同参数下的时间可以直接比较。
+func RandomTriple(rng *rand.Rand, p TripleParams) *System {
+	s := NewSystem()
+	for k := 0; k < 3; k++ {
+		r := math.Sqrt(rng.Float64())
+		th := rng.Float64() * 2 * math.Pi
+		s.Bodies = append(s.Bodies, Body{
+			Name:     string(rune('A' + k)),
+			Mass:     p.MassMin + rng.Float64()*(p.MassMax-p.MassMin),
+			Radius:   0.02,
+			Position: Vec2{r * math.Cos(th), r * math.Sin(th)},
+			Velocity: Vec2{rng.NormFloat64(), rng.NormFloat64()},
+		})
+	}
+	s.ToCenterOfMassFrame()
+
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		tangent := Vec2{-b.Position.Y, b.Position.X}.Normalize().Mult(b.Velocity.Length())
+		b.Velocity = b.Velocity.Mult(1 - math.Abs(p.Spin)).Add(tangent.Mult(p.Spin))
+	}
+	s.ToCenterOfMassFrame()
+
+	s.virialize(p.Virial)
+	s.henonUnits()
+	return s
+}
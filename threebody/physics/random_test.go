@@ -0,0 +1,23 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomTripleNormalization(t *testing.T) {
+	p := TripleParams{MassMin: 0.5, MassMax: 1.5, Virial: 0.3, Spin: 0.5}
+	for seed := int64(1); seed <= 20; seed++ {
+		s := RandomTriple(rand.New(rand.NewSource(seed)), p)
+		if e := s.Energy(); math.Abs(e+0.25) > 1e-12 {
+			t.Errorf("seed %d: E = %g, want -1/4", seed, e)
+		}
+		if q := s.KineticEnergy() / -s.PotentialEnergy(); math.Abs(q-p.Virial) > 1e-12 {
+			t.Errorf("seed %d: virial ratio = %g, want %g", seed, q, p.Virial)
+		}
+		if m := s.Momentum().Length(); m > 1e-12 {
+			t.Errorf("seed %d: momentum = %g, want 0", seed, m)
+		}
+	}
+}
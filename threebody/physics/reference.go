@@ -0,0 +1,237 @@
+package physics
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// KahanRK4 是用 Kahan 补偿求和累加位置、速度和时间的四阶龙格-库塔法。
+// 步长很小、步数很多时，普通的 x += dx 每步都丢掉 dx 的低位，
+// 补偿求和把丢掉的部分留到下一步，舍入误差不再随步数线性增长。
+// 系统状态在两步之间被外部修改时补偿量自动清零。
+type KahanRK4 struct {
+	rk4    RK4
+	cx, cv []Vec2  // 位置和速度的补偿量
+	ct     float64 // 时间的补偿量
+	last   []Body  // 上一步结束时的状态，用来发现外部修改
+}
+
+func (k *KahanRK4) Name() string { return "rk4-kahan" }
+
+func (k *KahanRK4) Step(s *System, dt float64) {
+	n := len(s.Bodies)
+	if len(k.last) != n || !sameState(k.last, s.Bodies) {
+		k.cx = make([]Vec2, n)
+		k.cv = make([]Vec2, n)
+		k.ct = 0
+	}
+	r := &k.rk4
+	r.slopes(s, dt)
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		b.Position = kahanAdd(r.x0[i], r.sumX[i].Mult(dt/6), &k.cx[i])
+		b.Velocity = kahanAdd(r.v0[i], r.sumV[i].Mult(dt/6), &k.cv[i])
+	}
+	y := dt - k.ct
+	t := s.Time + y
+	k.ct = (t - s.Time) - y
+	s.Time = t
+	k.last = append(k.last[:0], s.Bodies...)
+}
+
+// kahanAdd 返回 sum + d，c 是累积的补偿量
+func kahanAdd(sum, d Vec2, c *Vec2) Vec2 {
+	y := d.Sub(*c)
+	t := sum.Add(y)
+	*c = t.Sub(sum).Sub(y)
+	return t
+}
+
+func sameState(a, b []Body) bool {
+	for i := range a {
+		if a[i].Position != b[i].Position || a[i].Velocity != b[i].Velocity {
+			return false
+		}
+	}
+	return true
+}
+
+// BigRK4 是用 math/big.Float 做全部运算的四阶龙格-库塔法，作为参考解使用，
+// 比 float64 慢两个数量级以上。只支持牛顿引力（含软化），忽略 Forces。
+// 内部保留高精度状态，每步结束后把舍入到 float64 的结果写回 s；
+// 发现 s 被外部修改时从 s 重新载入。
+type BigRK4 struct {
+	Prec uint // 尾数位数，为零时取 256
+
+	x, v []*big.Float // 按 x0, y0, x1, y1, ... 排列
+	last []Body
+}
+
+func (b *BigRK4) Name() string { return "rk4-big" }
+
+// CheckIntegrator 检查 integ 能否积分 s。BigRK4 只有牛顿引力，s 的 Forces 里有别的力时
+// 会悄悄丢掉它们，所以报错；MassVarying 按里面的积分器检查
+func CheckIntegrator(integ Integrator, s *System) error {
+	if m, ok := integ.(MassVarying); ok {
+		integ = m.Integrator
+	}
+	if _, ok := integ.(*BigRK4); !ok {
+		return nil
+	}
+	for _, f := range s.Forces {
+		if _, ok := f.(Newtonian); !ok {
+			return fmt.Errorf("%s only supports plain gravity, not %s; drop -forces or pick another integrator", integ.Name(), f.Name())
+		}
+	}
+	return nil
+}
+
+func (b *BigRK4) prec() uint {
+	if b.Prec == 0 {
+		return 256
+	}
+	return b.Prec
+}
+
+func (b *BigRK4) newFloat(v float64) *big.Float {
+	return new(big.Float).SetPrec(b.prec()).SetFloat64(v)
+}
+
+// load 从 s 载入高精度状态
+func (b *BigRK4) load(s *System) {
+	b.x = b.x[:0]
+	b.v = b.v[:0]
+	for i := range s.Bodies {
+		p, v := s.Bodies[i].Position, s.Bodies[i].Velocity
+		b.x = append(b.x, b.newFloat(p.X), b.newFloat(p.Y))
+		b.v = append(b.v, b.newFloat(v.X), b.newFloat(v.Y))
+	}
+}
+
+// accelerations 计算位于 x 时的引力加速度
+func (b *BigRK4) accelerations(s *System, x []*big.Float) []*big.Float {
+	prec := b.prec()
+	acc := make([]*big.Float, len(x))
+	for k := range acc {
+		acc[k] = new(big.Float).SetPrec(prec)
+	}
+	eps2 := b.newFloat(s.Softening * s.Softening)
+	dx, dy := new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)
+	r2, r, f, t := new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)
+	src := s.Massive()
+	for i := range s.Bodies {
+		for _, j := range src {
+			if j == i {
+				continue
+			}
+			dx.Sub(x[2*j], x[2*i])
+			dy.Sub(x[2*j+1], x[2*i+1])
+			r2.Mul(dx, dx)
+			r2.Add(r2, t.Mul(dy, dy))
+			r2.Add(r2, eps2)
+			if r2.Sign() == 0 {
+				continue
+			}
+			r.Sqrt(r2)
+			// f = G·m_j / (r²·r)
+			f.Quo(b.newFloat(s.G*s.Bodies[j].Mass), t.Mul(r2, r))
+			acc[2*i].Add(acc[2*i], t.Mul(dx, f))
+			acc[2*i+1].Add(acc[2*i+1], t.Mul(dy, f))
+		}
+	}
+	return acc
+}
+
+func (b *BigRK4) Step(s *System, dt float64) {
+	if len(b.last) != len(s.Bodies) || !sameState(b.last, s.Bodies) {
+		b.load(s)
+	}
+	prec := b.prec()
+	h := b.newFloat(dt)
+	half := b.newFloat(dt / 2)
+	sixth := new(big.Float).SetPrec(prec).Quo(h, b.newFloat(6))
+	two := b.newFloat(2)
+
+	// axpy 返回 a + c·d
+	axpy := func(a []*big.Float, c *big.Float, d []*big.Float) []*big.Float {
+		out := make([]*big.Float, len(a))
+		for k := range a {
+			out[k] = new(big.Float).SetPrec(prec).Mul(c, d[k])
+			out[k].Add(out[k], a[k])
+		}
+		return out
+	}
+
+	k1x, k1v := b.v, b.accelerations(s, b.x)
+	k2x := axpy(b.v, half, k1v)
+	k2v := b.accelerations(s, axpy(b.x, half, k1x))
+	k3x := axpy(b.v, half, k2v)
+	k3v := b.accelerations(s, axpy(b.x, half, k2x))
+	k4x := axpy(b.v, h, k3v)
+	k4v := b.accelerations(s, axpy(b.x, h, k3x))
+
+	// sum 返回 k1 + 2k2 + 2k3 + k4
+	sum := func(k1, k2, k3, k4 []*big.Float) []*big.Float {
+		out := make([]*big.Float, len(k1))
+		t := new(big.Float).SetPrec(prec)
+		for k := range out {
+			out[k] = new(big.Float).SetPrec(prec).Add(k2[k], k3[k])
+			out[k].Mul(out[k], two)
+			out[k].Add(out[k], t.Add(k1[k], k4[k]))
+		}
+		return out
+	}
+	b.x = axpy(b.x, sixth, sum(k1x, k2x, k3x, k4x))
+	b.v = axpy(b.v, sixth, sum(k1v, k2v, k3v, k4v))
+
+	for i := range s.Bodies {
+		x, _ := b.x[2*i].Float64()
+		y, _ := b.x[2*i+1].Float64()
+		vx, _ := b.v[2*i].Float64()
+		vy, _ := b.v[2*i+1].Float64()
+		s.Bodies[i].Position = Vec2{x, y}
+		s.Bodies[i].Velocity = Vec2{vx, vy}
+	}
+	s.Time += dt
+	b.last = append(b.last[:0], s.Bodies...)
+}
+
+// DivergenceSample 是对比运行中的一次采样
+type DivergenceSample struct {
+	Time     float64
+	Distance float64 // 有质量天体与参考解的相空间距离
+}
+
+// Divergence 是一次对比运行的结果
+type Divergence struct {
+	Samples  []DivergenceSample
+	Diverged bool    // 距离是否超过过容差
+	Time     float64 // 第一次超过容差的时刻
+}
+
+// CompareWithReference 从 initial 的两个副本出发，分别用 integ（步长 dt）和
+// ref（步长 dt/refine）积分 duration，每 every 步比较一次相空间距离，
+// 记录第一次超过 tol 的时刻。initial 不会被修改。
+func CompareWithReference(initial *System, integ, ref Integrator, dt float64, refine int, duration, tol float64, every int) Divergence {
+	a, b := initial.Clone(), initial.Clone()
+	bodies := initial.Clone().Massive()
+	refine = max(refine, 1)
+	every = max(every, 1)
+	var d Divergence
+	n := int(duration/dt + 0.5)
+	for k := 1; k <= n; k++ {
+		integ.Step(a, dt)
+		for j := 0; j < refine; j++ {
+			ref.Step(b, dt/float64(refine))
+		}
+		if k%every != 0 && k != n {
+			continue
+		}
+		dist := PhaseDistance(a, b, bodies)
+		d.Samples = append(d.Samples, DivergenceSample{Time: a.Time, Distance: dist})
+		if dist > tol && !d.Diverged {
+			d.Diverged, d.Time = true, a.Time
+		}
+	}
+	return d
+}
@@ -0,0 +1,68 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 小步长长时间积分圆轨道时误差以舍入为主，补偿求和应当把它压低几个数量级
+func TestKahanRoundoff(t *testing.T) {
+	errs := map[string]float64{}
+	for _, in := range []Integrator{&RK4{}, &KahanRK4{}} {
+		s := &System{G: 1, Bodies: []Body{
+			{Name: "sun", Mass: 1},
+			{Name: "test", Position: Vec2{1, 0}, Velocity: Vec2{0, 1}},
+		}}
+		for k := 0; k < 300000; k++ {
+			in.Step(s, 1e-4)
+		}
+		want := Vec2{math.Cos(30), math.Sin(30)}
+		errs[in.Name()] = s.Bodies[1].Position.Sub(want).Length()
+	}
+	if errs["rk4-kahan"] > 1e-12 || errs["rk4-kahan"]*100 > errs["rk4"] {
+		t.Errorf("position error rk4 %.3e, rk4-kahan %.3e", errs["rk4"], errs["rk4-kahan"])
+	}
+}
+
+// 同样的步长下高精度版本和 float64 版本只差舍入误差
+func TestBigRK4(t *testing.T) {
+	p, _ := LookupPreset("figure8")
+	d := CompareWithReference(p.New(), &KahanRK4{}, &BigRK4{Prec: 128}, 0.01, 1, 2, 1e-12, 50)
+	if d.Diverged {
+		t.Errorf("rk4-kahan and rk4-big diverged at t=%g: %+v", d.Time, d.Samples)
+	}
+	if len(d.Samples) != 4 {
+		t.Errorf("got %d samples, want 4", len(d.Samples))
+	}
+}
+
+func TestCompareWithReference(t *testing.T) {
+	p, _ := LookupPreset("figure8")
+	s := p.New()
+	d := CompareWithReference(s, &Euler{}, &RK4{}, 0.01, 4, 10, 1e-3, 1)
+	if !d.Diverged || d.Time <= 0 || d.Time > 10 {
+		t.Errorf("euler should leave the reference within 10 time units: %v at %g", d.Diverged, d.Time)
+	}
+	if s.Time != 0 {
+		t.Errorf("initial system was modified, t = %g", s.Time)
+	}
+}
+
+func TestCheckIntegrator(t *testing.T) {
+	p, _ := LookupPreset("mercury")
+	s := p.New()
+	big := &BigRK4{}
+	if err := CheckIntegrator(big, s); err == nil {
+		t.Errorf("rk4-big accepted the 1pn term of %s", p.Name)
+	}
+	if err := CheckIntegrator(MassVarying{big}, s); err == nil {
+		t.Errorf("rk4-big wrapped in MassVarying accepted the 1pn term")
+	}
+	if err := CheckIntegrator(&RK4{}, s); err != nil {
+		t.Errorf("rk4: %v", err)
+	}
+	s.Forces = []ForceLaw{Newtonian{}}
+	if err := CheckIntegrator(big, s); err != nil {
+		t.Errorf("rk4-big with plain gravity: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package physics
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Regularized 在两颗有质量天体靠得比 Radius 更近时改用 Levi-Civita 正规化，
+// 其他时候交给 Base 积分。
+//
+// 正规化时把这一对的相对位置写成复数 r = u²，并换用虚拟时间 τ（dt = |r|·dτ），
+// 二体运动在 u 中变成简谐振动，碰撞处不再有奇点。其余天体和这一对的质心
+// 以同样的 τ 一起用四阶龙格-库塔法推进，其他天体对这一对的作用作为摄动 P：
+//
+//	u'' = (h/2)·u + (|u|²/2)·ū·P,  h' = Re(conj(2uu')·P),  t' = |u|²
+//
+// 其中 h 是这一对的二体能量（单位约化质量）。
+type Regularized struct {
+	Base     Integrator // 没有近距离交会时使用，为空时取 RK4
+	Radius   float64    // 两颗天体距离小于它时开启正规化，为零时取 0.3
+	PerOrbit int        // 正规化时每个 u 振动周期至少的子步数，为零时取 200
+
+	Pair  [2]int // 上一步正规化的天体对，没有时为 {-1, -1}
+	Steps int    // 累计正规化的步数
+
+	y, y0, k, sum []float64
+	pos, vel, acc []Vec2
+	others        []int
+}
+
+func (r *Regularized) Name() string { return "regularized" }
+
+func (r *Regularized) Step(s *System, dt float64) {
+	if r.Base == nil {
+		r.Base = &RK4{}
+	}
+	radius := r.Radius
+	if radius == 0 {
+		radius = 0.3
+	}
+	i, j := closestPair(s)
+	if i < 0 || s.Bodies[j].Position.Sub(s.Bodies[i].Position).Length() >= radius {
+		r.Pair = [2]int{-1, -1}
+		r.Base.Step(s, dt)
+		return
+	}
+	r.Pair = [2]int{i, j}
+	r.Steps++
+	r.step(s, i, j, dt)
+}
+
+// closestPair 返回距离最近的两颗有质量天体，不足两颗时返回 -1, -1
+func closestPair(s *System) (int, int) {
+	src := s.Massive()
+	bi, bj, best := -1, -1, math.Inf(1)
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			if d := s.Bodies[src[b]].Position.Sub(s.Bodies[src[a]].Position).Length2(); d < best {
+				bi, bj, best = src[a], src[b], d
+			}
+		}
+	}
+	return bi, bj
+}
+
+// 状态向量 y 的布局：u、u'（各两个分量）、h、t、质心位置和速度，
+// 然后是其余每个天体的位置和速度
+const (
+	lcU = iota * 2
+	lcUp
+	lcHT
+	lcR
+	lcV
+	lcOthers
+)
+
+func cplx(v Vec2) complex128     { return complex(v.X, v.Y) }
+func vec(c complex128) Vec2      { return Vec2{real(c), imag(c)} }
+func at(y []float64, k int) Vec2 { return Vec2{y[k], y[k+1]} }
+
+// step 用正规化坐标把系统恰好推进 dt
+func (r *Regularized) step(s *System, i, j int, dt float64) {
+	bi, bj := &s.Bodies[i], &s.Bodies[j]
+	m := bi.Mass + bj.Mass
+	gm := s.G * m
+
+	r.others = r.others[:0]
+	for k := range s.Bodies {
+		if k != i && k != j {
+			r.others = append(r.others, k)
+		}
+	}
+	n := lcOthers + 4*len(r.others)
+	r.y = growFloat(r.y, n)
+	r.y0 = growFloat(r.y0, n)
+	r.k = growFloat(r.k, n)
+	r.sum = growFloat(r.sum, n)
+	r.pos = grow(r.pos, len(s.Bodies))
+	r.vel = grow(r.vel, len(s.Bodies))
+	r.acc = grow(r.acc, len(s.Bodies))
+
+	// 从物理坐标换到正规化坐标
+	rel := cplx(bi.Position.Sub(bj.Position))
+	relV := cplx(bi.Velocity.Sub(bj.Velocity))
+	u := cmplx.Sqrt(rel)
+	up := cmplx.Conj(u) * relV / 2
+	h := 0.5*(real(relV)*real(relV)+imag(relV)*imag(relV)) - gm/cmplx.Abs(rel)
+	com := bi.Position.Mult(bi.Mass).Add(bj.Position.Mult(bj.Mass)).Mult(1 / m)
+	comV := bi.Velocity.Mult(bi.Mass).Add(bj.Velocity.Mult(bj.Mass)).Mult(1 / m)
+	y := r.y
+	y[lcU], y[lcU+1] = real(u), imag(u)
+	y[lcUp], y[lcUp+1] = real(up), imag(up)
+	y[lcHT], y[lcHT+1] = h, s.Time
+	y[lcR], y[lcR+1] = com.X, com.Y
+	y[lcV], y[lcV+1] = comV.X, comV.Y
+	for k, o := range r.others {
+		b := &s.Bodies[o]
+		y[lcOthers+4*k], y[lcOthers+4*k+1] = b.Position.X, b.Position.Y
+		y[lcOthers+4*k+2], y[lcOthers+4*k+3] = b.Velocity.X, b.Velocity.Y
+	}
+
+	// deriv 计算 dy/dτ
+	deriv := func(y, dy []float64) {
+		u, up := complex(y[lcU], y[lcU+1]), complex(y[lcUp], y[lcUp+1])
+		h := y[lcHT]
+		rabs := real(u)*real(u) + imag(u)*imag(u)
+		rel := u * u
+		relV := 2 * u * up / complex(rabs, 0)
+		com, comV := at(y, lcR), at(y, lcV)
+		r.pos[i], r.pos[j] = com.Add(vec(rel).Mult(bj.Mass/m)), com.Sub(vec(rel).Mult(bi.Mass/m))
+		r.vel[i], r.vel[j] = comV.Add(vec(relV).Mult(bj.Mass/m)), comV.Sub(vec(relV).Mult(bi.Mass/m))
+		for k, o := range r.others {
+			r.pos[o], r.vel[o] = at(y, lcOthers+4*k), at(y, lcOthers+4*k+2)
+		}
+		s.Accelerations(r.pos, r.vel, r.acc)
+
+		// 摄动 P = 相对加速度减去纯开普勒项（软化的差别也算在 P 里）
+		p := cplx(r.acc[i].Sub(r.acc[j])) + rel*complex(gm/(rabs*rabs*rabs), 0)
+		upp := complex(h/2, 0)*u + complex(rabs/2, 0)*cmplx.Conj(u)*p
+		dy[lcU], dy[lcU+1] = real(up), imag(up)
+		dy[lcUp], dy[lcUp+1] = real(upp), imag(upp)
+		dy[lcHT] = real(cmplx.Conj(2*u*up) * p)
+		dy[lcHT+1] = rabs
+		dy[lcR], dy[lcR+1] = comV.X*rabs, comV.Y*rabs
+		comA := r.acc[i].Mult(bi.Mass).Add(r.acc[j].Mult(bj.Mass)).Mult(rabs / m)
+		dy[lcV], dy[lcV+1] = comA.X, comA.Y
+		for k, o := range r.others {
+			q := lcOthers + 4*k
+			dy[q], dy[q+1] = y[q+2]*rabs, y[q+3]*rabs
+			dy[q+2], dy[q+3] = r.acc[o].X*rabs, r.acc[o].Y*rabs
+		}
+	}
+
+	perOrbit := r.PerOrbit
+	if perOrbit == 0 {
+		perOrbit = 200
+	}
+	tEnd := s.Time + dt
+	// 每个子步的物理时间不超过 dt，且 u 的每个振动周期至少 perOrbit 步；
+	// 最后几步按剩余时间调整 dτ，几次迭代后恰好停在 tEnd
+	for iter := 0; iter < 100000; iter++ {
+		left := tEnd - y[lcHT+1]
+		if math.Abs(left) <= 1e-14*math.Max(1, math.Abs(tEnd)) {
+			break
+		}
+		rabs := y[lcU]*y[lcU] + y[lcU+1]*y[lcU+1]
+		dtau := left / rabs
+		if omega := math.Sqrt(math.Abs(y[lcHT]) / 2); omega > 0 {
+			if limit := 2 * math.Pi / omega / float64(perOrbit); math.Abs(dtau) > limit {
+				dtau = math.Copysign(limit, dtau)
+			}
+		}
+		r.rk4(deriv, dtau)
+	}
+
+	// 换回物理坐标
+	u = complex(y[lcU], y[lcU+1])
+	up = complex(y[lcUp], y[lcUp+1])
+	rabs := real(u)*real(u) + imag(u)*imag(u)
+	rel, relV = u*u, 2*u*up/complex(rabs, 0)
+	com, comV = at(y, lcR), at(y, lcV)
+	bi.Position, bj.Position = com.Add(vec(rel).Mult(bj.Mass/m)), com.Sub(vec(rel).Mult(bi.Mass/m))
+	bi.Velocity, bj.Velocity = comV.Add(vec(relV).Mult(bj.Mass/m)), comV.Sub(vec(relV).Mult(bi.Mass/m))
+	for k, o := range r.others {
+		s.Bodies[o].Position, s.Bodies[o].Velocity = at(y, lcOthers+4*k), at(y, lcOthers+4*k+2)
+	}
+	s.Time = tEnd
+}
+
+// rk4 对 r.y 做一步长度为 h 的经典四阶龙格-库塔
+func (r *Regularized) rk4(deriv func(y, dy []float64), h float64) {
+	copy(r.y0, r.y)
+	deriv(r.y0, r.k)
+	copy(r.sum, r.k)
+	for _, st := range [...]struct{ c, w float64 }{{h / 2, 2}, {h / 2, 2}, {h, 1}} {
+		for q := range r.y {
+			r.y[q] = r.y0[q] + st.c*r.k[q]
+		}
+		deriv(r.y, r.k)
+		for q := range r.sum {
+			r.sum[q] += st.w * r.k[q]
+		}
+	}
+	for q := range r.y {
+		r.y[q] = r.y0[q] + h/6*r.sum[q]
+	}
+}
+
+// growFloat 返回长度为 n 的切片，尽量复用 buf
+func growFloat(buf []float64, n int) []float64 {
+	if cap(buf) < n {
+		return make([]float64, n)
+	}
+	return buf[:n]
+}
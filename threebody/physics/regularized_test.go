@@ -0,0 +1,53 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 偏心率 0.999 的二体轨道：固定步长的 RK4 在近心点失控，正规化后能量误差很小
+func TestRegularizedNearCollision(t *testing.T) {
+	const e = 0.999
+	newSystem := func() *System {
+		return NewSystem(
+			Body{Name: "a", Mass: 1},
+			Body{Name: "b", Mass: 1e-3, Position: Vec2{1 + e, 0}, Velocity: Vec2{0, math.Sqrt(1.001 * (1 - e) / (1 + e))}},
+		)
+	}
+	drift := func(integ Integrator) float64 {
+		s := newSystem()
+		e0 := s.Energy()
+		for k := 0; k < 20000; k++ {
+			integ.Step(s, 0.001)
+		}
+		if math.Abs(s.Time-20) > 1e-9 {
+			t.Errorf("%s: time %v after 20000 steps, want 20", integ.Name(), s.Time)
+		}
+		return math.Abs((s.Energy() - e0) / e0)
+	}
+	if d := drift(&RK4{}); d < 1e-2 {
+		t.Errorf("rk4 drift %.3e, expected the close pass to break it", d)
+	}
+	if d := drift(&Regularized{}); d > 1e-5 {
+		t.Errorf("regularized drift %.3e", d)
+	}
+}
+
+// 毕达哥拉斯三体问题里有多次近距离交会
+func TestRegularizedPythagorean(t *testing.T) {
+	p, _ := LookupPreset("pythagorean")
+	s := p.New()
+	integ := &Regularized{}
+	e0 := s.Energy()
+	worst := 0.0
+	for k := 0; k < 70000; k++ {
+		integ.Step(s, 0.001)
+		worst = math.Max(worst, math.Abs((s.Energy()-e0)/e0))
+	}
+	if integ.Steps == 0 {
+		t.Fatal("no step was regularized")
+	}
+	if worst > 1e-5 {
+		t.Errorf("worst relative energy error %.3e", worst)
+	}
+}
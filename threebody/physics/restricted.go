@@ -0,0 +1,111 @@
+package physics
+
+import "math"
+
+// Restricted 是圆型限制性三体问题，在与两颗主星一起旋转的坐标系里描述。
+// 单位取总质量 1、主星间距 1、角速度 1（G = 1），主星固定在
+// (-μ, 0) 和 (1-μ, 0)，Particles 是无质量的第三体，可以有多个。
+type Restricted struct {
+	Mu        float64 // 小主星的质量比 m2/(m1+m2)
+	Time      float64
+	Particles []Body
+}
+
+// NewRestricted 创建质量比为 mu 的限制性三体问题
+func NewRestricted(mu float64) *Restricted {
+	return &Restricted{Mu: mu}
+}
+
+// Primaries 返回两颗主星在旋转系中的位置
+func (r *Restricted) Primaries() (Vec2, Vec2) {
+	return Vec2{-r.Mu, 0}, Vec2{1 - r.Mu, 0}
+}
+
+// EffectivePotential 返回有效势 Ω = (x²+y²)/2 + (1-μ)/r1 + μ/r2
+func (r *Restricted) EffectivePotential(p Vec2) float64 {
+	p1, p2 := r.Primaries()
+	r1 := p.Sub(p1).Length()
+	r2 := p.Sub(p2).Length()
+	return 0.5*p.Length2() + (1-r.Mu)/r1 + r.Mu/r2
+}
+
+// Jacobi 返回雅可比常数 C = 2Ω - v²，旋转系中唯一的守恒量
+func (r *Restricted) Jacobi(b *Body) float64 {
+	return 2*r.EffectivePotential(b.Position) - b.Velocity.Length2()
+}
+
+// gradient 返回 ∇Ω，即引力加离心力
+func (r *Restricted) gradient(p Vec2) Vec2 {
+	p1, p2 := r.Primaries()
+	d1 := p.Sub(p1)
+	d2 := p.Sub(p2)
+	r1 := d1.Length()
+	r2 := d2.Length()
+	g := p.Sub(d1.Mult((1 - r.Mu) / (r1 * r1 * r1)))
+	return g.Sub(d2.Mult(r.Mu / (r2 * r2 * r2)))
+}
+
+// acceleration 返回旋转系中的加速度，包括科里奥利力 (2vy, -2vx)
+func (r *Restricted) acceleration(p, v Vec2) Vec2 {
+	return r.gradient(p).Add(Vec2{2 * v.Y, -2 * v.X})
+}
+
+// Step 用四阶龙格-库塔法推进所有粒子。
+// 科里奥利力依赖速度，所以这里不用只适合位置相关力的蛙跳法。
+func (r *Restricted) Step(dt float64) {
+	for i := range r.Particles {
+		b := &r.Particles[i]
+		x0, v0 := b.Position, b.Velocity
+
+		k1x, k1v := v0, r.acceleration(x0, v0)
+		x, v := x0.Add(k1x.Mult(dt/2)), v0.Add(k1v.Mult(dt/2))
+		k2x, k2v := v, r.acceleration(x, v)
+		x, v = x0.Add(k2x.Mult(dt/2)), v0.Add(k2v.Mult(dt/2))
+		k3x, k3v := v, r.acceleration(x, v)
+		x, v = x0.Add(k3x.Mult(dt)), v0.Add(k3v.Mult(dt))
+		k4x, k4v := v, r.acceleration(x, v)
+
+		b.Position = x0.Add(k1x.Add(k2x.Mult(2)).Add(k3x.Mult(2)).Add(k4x).Mult(dt / 6))
+		b.Velocity = v0.Add(k1v.Add(k2v.Mult(2)).Add(k3v.Mult(2)).Add(k4v).Mult(dt / 6))
+	}
+	r.Time += dt
+}
+
+// LagrangePoints 返回 L1 到 L5 五个拉格朗日点。
+// L4、L5 在等边三角形顶点上；L1、L2、L3 在 x 轴上，用牛顿法求 ∂Ω/∂x = 0。
+func (r *Restricted) LagrangePoints() [5]Vec2 {
+	mu := r.Mu
+	h := math.Cbrt(mu / 3) // 希尔半径，作为 L1、L2 的初始猜测
+	collinear := func(x float64) float64 {
+		for k := 0; k < 50; k++ {
+			f := r.gradient(Vec2{x, 0}).X
+			const eps = 1e-7
+			df := (r.gradient(Vec2{x + eps, 0}).X - r.gradient(Vec2{x - eps, 0}).X) / (2 * eps)
+			step := f / df
+			x -= step
+			if math.Abs(step) < 1e-14 {
+				break
+			}
+		}
+		return x
+	}
+	return [5]Vec2{
+		{collinear(1 - mu - h), 0},
+		{collinear(1 - mu + h), 0},
+		{collinear(-1 - 5*mu/12), 0},
+		{0.5 - mu, math.Sqrt(3) / 2},
+		{0.5 - mu, -math.Sqrt(3) / 2},
+	}
+}
+
+// AddCoorbital 在绕质心半径为 radius、相位角为 angle 的位置加入一个粒子，
+// 速度取惯性系中的圆轨道速度换到旋转系后的值。radius 略大于 1、
+// angle 在 60° 附近得到蝌蚪形轨道，angle 在 180° 附近得到马蹄形轨道。
+func (r *Restricted) AddCoorbital(radius, angle float64) {
+	dir := Vec2{math.Cos(angle), math.Sin(angle)}
+	v := math.Sqrt(1/radius) - radius
+	r.Particles = append(r.Particles, Body{
+		Position: dir.Mult(radius),
+		Velocity: Vec2{-dir.Y, dir.X}.Mult(v),
+	})
+}
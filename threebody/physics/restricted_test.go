@@ -0,0 +1,33 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLagrangePointsAreEquilibria(t *testing.T) {
+	for _, mu := range []float64{0.001, 0.01215, 0.3} {
+		r := NewRestricted(mu)
+		for k, p := range r.LagrangePoints() {
+			if g := r.gradient(p).Length(); g > 1e-10 {
+				t.Errorf("mu=%g: |grad Omega| at L%d = %g", mu, k+1, g)
+			}
+		}
+	}
+}
+
+func TestTadpoleStaysNearL4(t *testing.T) {
+	r := NewRestricted(0.001)
+	r.AddCoorbital(1.005, 70*math.Pi/180)
+	c0 := r.Jacobi(&r.Particles[0])
+	for k := 0; k < 100000; k++ {
+		r.Step(0.005)
+		p := r.Particles[0].Position
+		if a := math.Atan2(p.Y, p.X) * 180 / math.Pi; a < 30 || a > 120 {
+			t.Fatalf("t=%.1f: tadpole left the L4 region (angle %.1f°)", r.Time, a)
+		}
+	}
+	if dc := math.Abs(r.Jacobi(&r.Particles[0]) - c0); dc > 1e-9 {
+		t.Errorf("Jacobi constant drifted by %g", dc)
+	}
+}
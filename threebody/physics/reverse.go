@@ -0,0 +1,40 @@
+package physics
+
+import "math"
+
+// FlipVelocities 把所有天体的速度反向
+func (s *System) FlipVelocities() {
+	for i := range s.Bodies {
+		s.Bodies[i].Velocity = s.Bodies[i].Velocity.Mult(-1)
+	}
+}
+
+// Reversal 是时间可逆性检查的结果
+type Reversal struct {
+	Steps       int     // 正向和反向各走的步数
+	Distance    float64 // 回到起点后有质量天体与初始条件的相空间距离
+	EnergyError float64 // 往返之后的相对能量误差
+}
+
+// Reversibility 从 initial 的副本出发正向积分 duration，把速度反向后再积分
+// 同样的步数，速度再反向回来，报告与初始条件的差距。
+// 对称的积分器（verlet）只留下舍入误差；不对称的积分器或写错的力会留下明显的偏差。
+// 阻力之类的耗散力本身就不可逆。
+func Reversibility(initial *System, integ Integrator, dt, duration float64) Reversal {
+	s := initial.Clone()
+	n := int(math.Round(duration / dt))
+	for k := 0; k < n; k++ {
+		integ.Step(s, dt)
+	}
+	s.FlipVelocities()
+	for k := 0; k < n; k++ {
+		integ.Step(s, dt)
+	}
+	s.FlipVelocities()
+
+	r := Reversal{Steps: n, Distance: PhaseDistance(initial, s, initial.Clone().Massive())}
+	if e0 := initial.Energy(); e0 != 0 {
+		r.EnergyError = (s.Energy() - e0) / math.Abs(e0)
+	}
+	return r
+}
@@ -0,0 +1,21 @@
+package physics
+
+import "testing"
+
+// 蛙跳法是时间对称的，往返只剩舍入误差；半隐式欧拉法不是
+func TestReversibility(t *testing.T) {
+	p, _ := LookupPreset("figure8")
+	s := p.New()
+	if r := Reversibility(s, &Verlet{}, 0.001, 5); r.Distance > 1e-10 {
+		t.Errorf("verlet: distance %.3e after %d steps each way", r.Distance, r.Steps)
+	}
+	if r := Reversibility(s, &RK4{}, 0.001, 5); r.Distance > 1e-8 {
+		t.Errorf("rk4: distance %.3e", r.Distance)
+	}
+	if r := Reversibility(s, &Euler{}, 0.001, 5); r.Distance < 1e-4 {
+		t.Errorf("euler: distance %.3e, expected it to be irreversible", r.Distance)
+	}
+	if s.Time != 0 {
+		t.Errorf("initial system was modified, t = %g", s.Time)
+	}
+}
@@ -0,0 +1,79 @@
+package physics
+
+// vector 是 Vec2 和 Vec3 共有的运算。二维和三维积分器共用下面这些格式，
+// 只是求加速度的方式不同。
+type vector[V any] interface {
+	Add(V) V
+	Mult(float64) V
+}
+
+// accelFunc 按位置和速度求加速度写入 acc
+type accelFunc[V any] func(pos, vel, acc []V)
+
+// grow 返回长度为 n 的切片，尽量复用 buf
+func grow[V any](buf []V, n int) []V {
+	if cap(buf) < n {
+		return make([]V, n)
+	}
+	return buf[:n]
+}
+
+// eulerStep 用半隐式欧拉法原地推进 x、v：先更新速度再用新速度更新位置
+func eulerStep[V vector[V]](x, v, acc []V, accel accelFunc[V], dt float64) {
+	accel(x, v, acc)
+	for i := range x {
+		v[i] = v[i].Add(acc[i].Mult(dt))
+		x[i] = x[i].Add(v[i].Mult(dt))
+	}
+}
+
+// verletStep 用漂移-踢-漂移蛙跳法原地推进 x、v，加速度在半步位置、步初速度处求值
+func verletStep[V vector[V]](x, v, acc []V, accel accelFunc[V], dt float64) {
+	for i := range x {
+		x[i] = x[i].Add(v[i].Mult(dt / 2))
+	}
+	accel(x, v, acc)
+	for i := range x {
+		v[i] = v[i].Add(acc[i].Mult(dt))
+		x[i] = x[i].Add(v[i].Mult(dt / 2))
+	}
+}
+
+// rk4Stages 是经典四阶龙格-库塔法的缓冲
+type rk4Stages[V vector[V]] struct {
+	x0, v0, x, acc     []V
+	kx, kv, sumX, sumV []V
+}
+
+// slopes 从 x0、v0 出发求出四个阶段斜率的加权和 sumX、sumV，
+// 步末状态是 x0 + sumX·dt/6、v0 + sumV·dt/6
+func (r *rk4Stages[V]) slopes(accel accelFunc[V], dt float64) {
+	n := len(r.x0)
+	r.x = grow(r.x, n)
+	r.acc = grow(r.acc, n)
+	r.kx = grow(r.kx, n)
+	r.kv = grow(r.kv, n)
+	r.sumX = grow(r.sumX, n)
+	r.sumV = grow(r.sumV, n)
+
+	// k1 在起点求值；kx/kv 保存上一阶段的斜率
+	copy(r.kx, r.v0)
+	accel(r.x0, r.kx, r.acc)
+	copy(r.kv, r.acc)
+	copy(r.sumX, r.kx)
+	copy(r.sumV, r.kv)
+
+	stages := [...]struct{ h, w float64 }{{dt / 2, 2}, {dt / 2, 2}, {dt, 1}}
+	for _, st := range stages {
+		for i := 0; i < n; i++ {
+			r.x[i] = r.x0[i].Add(r.kx[i].Mult(st.h))
+			r.kx[i] = r.v0[i].Add(r.kv[i].Mult(st.h))
+		}
+		accel(r.x, r.kx, r.acc)
+		for i := 0; i < n; i++ {
+			r.kv[i] = r.acc[i]
+			r.sumX[i] = r.sumX[i].Add(r.kx[i].Mult(st.w))
+			r.sumV[i] = r.sumV[i].Add(r.kv[i].Mult(st.w))
+		}
+	}
+}
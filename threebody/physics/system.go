@@ -0,0 +1,236 @@
+// Package physics 是三体模拟共用的物理核心：状态、引力、积分器和诊断量。
+// 使用模拟单位（默认 G = 1），与具体的显示方式无关。
+package physics
+
+import "math"
+
+// Body 表示一个天体。质量为零的天体是测试粒子：受引力但不产生引力。
+type Body struct {
+	Name       string
+	Mass       float64
+	Radius     float64
+	Luminosity float64 // 光度，为零时按质光关系从质量估算
+	Charge     float64 // 电荷，只在 Forces 含 Coulomb 时起作用
+	Position   Vec2
+	Velocity   Vec2
+}
+
+// IsTest 判断是否为测试粒子
+func (b *Body) IsTest() bool {
+	return b.Mass == 0
+}
+
+// System 表示一个引力系统
+type System struct {
+	G         float64 // 引力常数
+	Softening float64 // 软化长度，避免近距离时加速度发散
+	Time      float64 // 模拟时间
+	Bodies    []Body
+	Forces    []ForceLaw // 作用在天体上的力，为空时只有牛顿引力
+	MassLoss  []MassLoss // 质量随时间变化的天体，要配合 MassVarying 使用
+
+	sources []int // 有质量天体的下标缓存
+}
+
+// NewSystem 用给定天体创建系统，G 取 1
+func NewSystem(bodies ...Body) *System {
+	return &System{G: 1, Bodies: bodies}
+}
+
+// Clone 深拷贝系统
+func (s *System) Clone() *System {
+	c := *s
+	c.Bodies = append([]Body(nil), s.Bodies...)
+	c.Forces = append([]ForceLaw(nil), s.Forces...)
+	c.MassLoss = append([]MassLoss(nil), s.MassLoss...)
+	c.sources = nil
+	return &c
+}
+
+// Massive 返回有质量天体的下标
+func (s *System) Massive() []int {
+	s.sources = s.sources[:0]
+	for i := range s.Bodies {
+		if !s.Bodies[i].IsTest() {
+			s.sources = append(s.sources, i)
+		}
+	}
+	return s.sources
+}
+
+// Positions 把所有天体的位置写入 dst 并返回
+func (s *System) Positions(dst []Vec2) []Vec2 {
+	dst = dst[:0]
+	for i := range s.Bodies {
+		dst = append(dst, s.Bodies[i].Position)
+	}
+	return dst
+}
+
+// Velocities 把所有天体的速度写入 dst 并返回
+func (s *System) Velocities(dst []Vec2) []Vec2 {
+	dst = dst[:0]
+	for i := range s.Bodies {
+		dst = append(dst, s.Bodies[i].Velocity)
+	}
+	return dst
+}
+
+// setState 把 pos、vel 写回各个天体
+func (s *System) setState(pos, vel []Vec2) {
+	for i := range s.Bodies {
+		s.Bodies[i].Position = pos[i]
+		s.Bodies[i].Velocity = vel[i]
+	}
+}
+
+var defaultForces = []ForceLaw{Newtonian{}}
+
+// forces 返回实际生效的力
+func (s *System) forces() []ForceLaw {
+	if len(s.Forces) == 0 {
+		return defaultForces
+	}
+	return s.Forces
+}
+
+// Accelerations 计算天体位于 pos、速度为 vel 时的加速度，写入 acc。
+// 只有阻力之类的速度相关力会用到 vel。
+func (s *System) Accelerations(pos, vel, acc []Vec2) {
+	for i := range acc {
+		acc[i] = Vec2{}
+	}
+	for _, f := range s.forces() {
+		f.Accumulate(s, pos, vel, acc)
+	}
+}
+
+// KineticEnergy 返回总动能
+func (s *System) KineticEnergy() float64 {
+	e := 0.0
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		e += 0.5 * b.Mass * b.Velocity.Length2()
+	}
+	return e
+}
+
+// PotentialEnergy 返回所有保守力的势能之和，默认只有引力势能
+func (s *System) PotentialEnergy() float64 {
+	e := 0.0
+	for _, f := range s.forces() {
+		if c, ok := f.(Conservative); ok {
+			e += c.Potential(s)
+		}
+	}
+	return e
+}
+
+// Energy 返回总能量
+func (s *System) Energy() float64 {
+	return s.KineticEnergy() + s.PotentialEnergy()
+}
+
+// TotalMass 返回总质量
+func (s *System) TotalMass() float64 {
+	m := 0.0
+	for i := range s.Bodies {
+		m += s.Bodies[i].Mass
+	}
+	return m
+}
+
+// Momentum 返回总动量
+func (s *System) Momentum() Vec2 {
+	var p Vec2
+	for i := range s.Bodies {
+		p = p.Add(s.Bodies[i].Velocity.Mult(s.Bodies[i].Mass))
+	}
+	return p
+}
+
+// AngularMomentum 返回相对原点的总角动量（z 分量）
+func (s *System) AngularMomentum() float64 {
+	l := 0.0
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		l += b.Mass * b.Position.Cross(b.Velocity)
+	}
+	return l
+}
+
+// CenterOfMass 返回质心位置和质心速度
+func (s *System) CenterOfMass() (Vec2, Vec2) {
+	m := s.TotalMass()
+	if m == 0 {
+		return Vec2{}, Vec2{}
+	}
+	var r, v Vec2
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		r = r.Add(b.Position.Mult(b.Mass))
+		v = v.Add(b.Velocity.Mult(b.Mass))
+	}
+	return r.Mult(1 / m), v.Mult(1 / m)
+}
+
+// ToCenterOfMassFrame 把所有天体（包括测试粒子）平移到质心系
+func (s *System) ToCenterOfMassFrame() {
+	r, v := s.CenterOfMass()
+	for i := range s.Bodies {
+		s.Bodies[i].Position = s.Bodies[i].Position.Sub(r)
+		s.Bodies[i].Velocity = s.Bodies[i].Velocity.Sub(v)
+	}
+}
+
+// Index 按名字查找天体下标，找不到时返回 -1
+func (s *System) Index(name string) int {
+	for i := range s.Bodies {
+		if s.Bodies[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FieldAt 返回位于 p 的测试粒子受到的引力加速度（只算牛顿引力）
+func (s *System) FieldAt(p Vec2) Vec2 {
+	eps2 := s.Softening * s.Softening
+	var a Vec2
+	for _, j := range s.Massive() {
+		d := s.Bodies[j].Position.Sub(p)
+		r2 := d.Length2() + eps2
+		if r2 == 0 {
+			continue
+		}
+		a = a.Add(d.Mult(s.G * s.Bodies[j].Mass / (r2 * math.Sqrt(r2))))
+	}
+	return a
+}
+
+// PotentialAt 返回 p 处的引力势 Φ = -Σ G·m/r
+func (s *System) PotentialAt(p Vec2) float64 {
+	eps2 := s.Softening * s.Softening
+	phi := 0.0
+	for _, j := range s.Massive() {
+		r := math.Sqrt(s.Bodies[j].Position.Sub(p).Length2() + eps2)
+		if r == 0 {
+			continue
+		}
+		phi -= s.G * s.Bodies[j].Mass / r
+	}
+	return phi
+}
+
+// PairAcceleration 返回 j 号天体在 i 号天体处产生的引力加速度
+func (s *System) PairAcceleration(i, j int) Vec2 {
+	if i == j || s.Bodies[j].IsTest() {
+		return Vec2{}
+	}
+	d := s.Bodies[j].Position.Sub(s.Bodies[i].Position)
+	r2 := d.Length2() + s.Softening*s.Softening
+	if r2 == 0 {
+		return Vec2{}
+	}
+	return d.Mult(s.G * s.Bodies[j].Mass / (r2 * math.Sqrt(r2)))
+}
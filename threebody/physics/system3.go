@@ -0,0 +1,284 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Body3 是三维模式里的天体，质量为零的是测试粒子
+type Body3 struct {
+	Name     string
+	Mass     float64
+	Radius   float64
+	Position Vec3
+	Velocity Vec3
+}
+
+// IsTest 判断是否为测试粒子
+func (b *Body3) IsTest() bool {
+	return b.Mass == 0
+}
+
+// System3 是三维的引力系统。三维模式只有牛顿引力，不支持 Forces。
+type System3 struct {
+	G         float64
+	Softening float64
+	Time      float64
+	Bodies    []Body3
+
+	sources []int
+}
+
+// NewSystem3 用给定天体创建三维系统，G 取 1
+func NewSystem3(bodies ...Body3) *System3 {
+	return &System3{G: 1, Bodies: bodies}
+}
+
+// Liftable 检查 s 能否原样放进三维空间：三维模式只有牛顿引力，
+// 其他力、电荷和质量变化都会被 Lift 丢掉
+func Liftable(s *System) error {
+	for _, f := range s.Forces {
+		if _, ok := f.(Newtonian); !ok {
+			return fmt.Errorf("3D mode has plain gravity only, cannot carry the %s force", f.Name())
+		}
+	}
+	if len(s.MassLoss) > 0 {
+		return fmt.Errorf("3D mode does not support mass loss")
+	}
+	return nil
+}
+
+// Lift 把二维系统放进三维空间的 xy 平面，再绕 x 轴倾斜 tilt 弧度。
+// 只保留牛顿引力，先用 Liftable 检查
+func Lift(s *System, tilt float64) *System3 {
+	s3 := &System3{G: s.G, Softening: s.Softening, Time: s.Time}
+	for _, b := range s.Bodies {
+		s3.Bodies = append(s3.Bodies, Body3{
+			Name:     b.Name,
+			Mass:     b.Mass,
+			Radius:   b.Radius,
+			Position: Vec3{b.Position.X, b.Position.Y, 0}.RotateX(tilt),
+			Velocity: Vec3{b.Velocity.X, b.Velocity.Y, 0}.RotateX(tilt),
+		})
+	}
+	return s3
+}
+
+// Clone 深拷贝系统
+func (s *System3) Clone() *System3 {
+	c := *s
+	c.Bodies = append([]Body3(nil), s.Bodies...)
+	c.sources = nil
+	return &c
+}
+
+// Massive 返回有质量天体的下标
+func (s *System3) Massive() []int {
+	s.sources = s.sources[:0]
+	for i := range s.Bodies {
+		if !s.Bodies[i].IsTest() {
+			s.sources = append(s.sources, i)
+		}
+	}
+	return s.sources
+}
+
+// Positions 把所有天体的位置写入 dst 并返回
+func (s *System3) Positions(dst []Vec3) []Vec3 {
+	dst = dst[:0]
+	for i := range s.Bodies {
+		dst = append(dst, s.Bodies[i].Position)
+	}
+	return dst
+}
+
+// Velocities 把所有天体的速度写入 dst 并返回
+func (s *System3) Velocities(dst []Vec3) []Vec3 {
+	dst = dst[:0]
+	for i := range s.Bodies {
+		dst = append(dst, s.Bodies[i].Velocity)
+	}
+	return dst
+}
+
+// setState 把 pos、vel 写回各个天体
+func (s *System3) setState(pos, vel []Vec3) {
+	for i := range s.Bodies {
+		s.Bodies[i].Position = pos[i]
+		s.Bodies[i].Velocity = vel[i]
+	}
+}
+
+// Accelerations 计算天体位于 pos 时的引力加速度，写入 acc
+func (s *System3) Accelerations(pos, acc []Vec3) {
+	eps2 := s.Softening * s.Softening
+	src := s.Massive()
+	for i := range pos {
+		var a Vec3
+		for _, j := range src {
+			if j == i {
+				continue
+			}
+			d := pos[j].Sub(pos[i])
+			r2 := d.Length2() + eps2
+			if r2 == 0 {
+				continue
+			}
+			a = a.Add(d.Mult(s.G * s.Bodies[j].Mass / (r2 * math.Sqrt(r2))))
+		}
+		acc[i] = a
+	}
+}
+
+// KineticEnergy 返回总动能
+func (s *System3) KineticEnergy() float64 {
+	e := 0.0
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		e += 0.5 * b.Mass * b.Velocity.Length2()
+	}
+	return e
+}
+
+// PotentialEnergy 返回总引力势能（与 Softening 一致）
+func (s *System3) PotentialEnergy() float64 {
+	eps2 := s.Softening * s.Softening
+	src := s.Massive()
+	e := 0.0
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			bi, bj := &s.Bodies[src[a]], &s.Bodies[src[b]]
+			r := math.Sqrt(bj.Position.Sub(bi.Position).Length2() + eps2)
+			e -= s.G * bi.Mass * bj.Mass / r
+		}
+	}
+	return e
+}
+
+// Energy 返回总能量
+func (s *System3) Energy() float64 {
+	return s.KineticEnergy() + s.PotentialEnergy()
+}
+
+// TotalMass 返回总质量
+func (s *System3) TotalMass() float64 {
+	m := 0.0
+	for i := range s.Bodies {
+		m += s.Bodies[i].Mass
+	}
+	return m
+}
+
+// Momentum 返回总动量
+func (s *System3) Momentum() Vec3 {
+	var p Vec3
+	for i := range s.Bodies {
+		p = p.Add(s.Bodies[i].Velocity.Mult(s.Bodies[i].Mass))
+	}
+	return p
+}
+
+// AngularMomentum 返回相对原点的总角动量
+func (s *System3) AngularMomentum() Vec3 {
+	var l Vec3
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		l = l.Add(b.Position.Cross(b.Velocity).Mult(b.Mass))
+	}
+	return l
+}
+
+// CenterOfMass 返回质心位置和质心速度
+func (s *System3) CenterOfMass() (Vec3, Vec3) {
+	m := s.TotalMass()
+	if m == 0 {
+		return Vec3{}, Vec3{}
+	}
+	var r, v Vec3
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		r = r.Add(b.Position.Mult(b.Mass))
+		v = v.Add(b.Velocity.Mult(b.Mass))
+	}
+	return r.Mult(1 / m), v.Mult(1 / m)
+}
+
+// ToCenterOfMassFrame 把所有天体平移到质心系
+func (s *System3) ToCenterOfMassFrame() {
+	r, v := s.CenterOfMass()
+	for i := range s.Bodies {
+		s.Bodies[i].Position = s.Bodies[i].Position.Sub(r)
+		s.Bodies[i].Velocity = s.Bodies[i].Velocity.Sub(v)
+	}
+}
+
+// Index 按名字查找天体下标，找不到时返回 -1
+func (s *System3) Index(name string) int {
+	for i := range s.Bodies {
+		if s.Bodies[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Elements3 是三维两体密切轨道根数中用来观察的几个
+type Elements3 struct {
+	I, J         int
+	SemiMajor    float64
+	Eccentricity float64
+	Inclination  float64 // 轨道面与不变平面（垂直于总角动量）的夹角（弧度）
+}
+
+// OrbitalElements 计算 i 相对 j 的密切轨道根数
+func (s *System3) OrbitalElements(i, j int) Elements3 {
+	return s.orbitalElements(i, j, s.invariableAxis())
+}
+
+// invariableAxis 返回总角动量方向，总角动量为零时退回到 z 轴（xy 平面）
+func (s *System3) invariableAxis() Vec3 {
+	axis := s.AngularMomentum().Normalize()
+	if axis == (Vec3{}) {
+		axis = Vec3{0, 0, 1}
+	}
+	return axis
+}
+
+// orbitalElements 计算 i 相对 j 的密切轨道根数，倾角相对 axis
+func (s *System3) orbitalElements(i, j int, axis Vec3) Elements3 {
+	bi, bj := &s.Bodies[i], &s.Bodies[j]
+	mu := s.G * (bi.Mass + bj.Mass)
+	r := bi.Position.Sub(bj.Position)
+	v := bi.Velocity.Sub(bj.Velocity)
+	h := r.Cross(v)
+	ecc := v.Cross(h).Mult(1 / mu).Sub(r.Normalize())
+	el := Elements3{
+		I: i, J: j,
+		SemiMajor:    1 / (2/r.Length() - v.Length2()/mu),
+		Eccentricity: ecc.Length(),
+	}
+	if l := h.Length(); l > 0 {
+		el.Inclination = math.Acos(math.Max(-1, math.Min(1, h.Dot(axis)/l)))
+	}
+	return el
+}
+
+// TightestPair 在有质量天体中找出半长轴最小的束缚对，J 取较重的一个。
+// 要遍历所有天体对，开销是 O(N²)
+func (s *System3) TightestPair() (el Elements3, ok bool) {
+	axis := s.invariableAxis()
+	src := s.Massive()
+	for a := 0; a < len(src); a++ {
+		for b := a + 1; b < len(src); b++ {
+			i, j := src[a], src[b]
+			if s.Bodies[i].Mass > s.Bodies[j].Mass {
+				i, j = j, i
+			}
+			e := s.orbitalElements(i, j, axis)
+			if e.SemiMajor > 0 && (!ok || e.SemiMajor < el.SemiMajor) {
+				el, ok = e, true
+			}
+		}
+	}
+	return el, ok
+}
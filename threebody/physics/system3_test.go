@@ -0,0 +1,59 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// 放进三维并倾斜后的平面系统应该和二维积分结果一致
+func TestLiftMatchesPlanar(t *testing.T) {
+	const tilt = 0.4
+	p, _ := LookupPreset("figure8")
+	s2 := p.New()
+	s3 := Lift(s2, tilt)
+	i2, i3 := &RK4{}, &RK4In3D{}
+	for k := 0; k < 2000; k++ {
+		i2.Step(s2, 0.001)
+		i3.Step(s3, 0.001)
+	}
+	for i := range s2.Bodies {
+		b := s3.Bodies[i].Position.RotateX(-tilt)
+		if math.Abs(b.Z) > 1e-12 || b.XY().Sub(s2.Bodies[i].Position).Length() > 1e-12 {
+			t.Errorf("body %d: 3D %v, planar %v", i, b, s2.Bodies[i].Position)
+		}
+	}
+}
+
+// 非平面系统的能量、动量和角动量守恒
+func TestSystem3Conservation(t *testing.T) {
+	p, _ := LookupPreset3("kozai")
+	s := p.New()
+	e0, l0 := s.Energy(), s.AngularMomentum()
+	integ := &Verlet3{}
+	for k := 0; k < 60000; k++ {
+		integ.Step(s, 0.005)
+	}
+	if d := math.Abs((s.Energy() - e0) / e0); d > 1e-4 {
+		t.Errorf("relative energy drift %.3e", d)
+	}
+	if d := s.AngularMomentum().Sub(l0).Length() / l0.Length(); d > 1e-10 {
+		t.Errorf("relative angular momentum drift %.3e", d)
+	}
+	if p := s.Momentum().Length(); p > 1e-12 {
+		t.Errorf("momentum %.3e", p)
+	}
+	// 伴星倾斜 65°，行星的偏心率会被 Kozai-Lidov 机制抬高
+	if e := s.OrbitalElements(1, 0).Eccentricity; e < 0.5 {
+		t.Errorf("planet eccentricity %.3f after t=300, expected Kozai growth", e)
+	}
+}
+
+// 带 1PN 的 mercury 不能悄悄变成纯牛顿的三维场景
+func TestLookupPreset3Forces(t *testing.T) {
+	if _, err := LookupPreset3("mercury"); err == nil {
+		t.Error("mercury lifted into 3D without its 1pn term")
+	}
+	if _, err := LookupPreset3("figure8"); err != nil {
+		t.Error(err)
+	}
+}
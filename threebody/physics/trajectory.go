@@ -0,0 +1,84 @@
+package physics
+
+// Trajectories 记录一次运行中有质量天体和行星的完整轨迹，不像尾迹那样只保留最近一段。
+// 测试粒子太多，不记录。
+type Trajectories struct {
+	Bodies     []int    // 记录的天体下标
+	Names      []string // 对应的天体名字
+	Paths      [][]Vec2 // 每个天体按时间顺序的位置
+	Start, End float64  // 第一个和最后一个采样点的时刻
+	Every      int      // 每调用 Every 次 Record 采样一次，为零时每次都采样
+	MinStep    float64  // 离上一个记录点不到 MinStep 的位置不记录，控制长时间运行的内存
+
+	calls int
+}
+
+// NewTrajectories 选出 s 里要记录的天体，并记下它们的起点
+func NewTrajectories(s *System) *Trajectories {
+	t := &Trajectories{}
+	for i := range s.Bodies {
+		b := &s.Bodies[i]
+		if b.IsTest() && b.Name != "planet" {
+			continue
+		}
+		t.Bodies = append(t.Bodies, i)
+		t.Names = append(t.Names, b.Name)
+	}
+	t.Paths = make([][]Vec2, len(t.Bodies))
+	t.Start = s.Time
+	t.sample(s)
+	return t
+}
+
+// Record 在每一步（或每一帧）之后调用
+func (t *Trajectories) Record(s *System) {
+	t.calls++
+	if t.Every > 1 && t.calls%t.Every != 0 {
+		return
+	}
+	t.sample(s)
+}
+
+// Finish 在运行结束时调用：最后一次 Record 被 Every 或 MinStep 跳过时补上终点，
+// 保证每条轨迹都停在最终位置
+func (t *Trajectories) Finish(s *System) {
+	for k, i := range t.Bodies {
+		if i >= len(s.Bodies) {
+			continue
+		}
+		p := s.Bodies[i].Position
+		if n := len(t.Paths[k]); n == 0 || t.Paths[k][n-1] != p {
+			t.Paths[k] = append(t.Paths[k], p)
+		}
+	}
+	t.End = s.Time
+}
+
+func (t *Trajectories) sample(s *System) {
+	for k, i := range t.Bodies {
+		if i >= len(s.Bodies) {
+			continue
+		}
+		p := s.Bodies[i].Position
+		if n := len(t.Paths[k]); n > 0 && p.Sub(t.Paths[k][n-1]).Length() < t.MinStep {
+			continue
+		}
+		t.Paths[k] = append(t.Paths[k], p)
+	}
+	t.End = s.Time
+}
+
+// Bounds 返回所有轨迹点的包围盒，没有点时 ok 为 false
+func (t *Trajectories) Bounds() (lo, hi Vec2, ok bool) {
+	for _, path := range t.Paths {
+		for _, p := range path {
+			if !ok {
+				lo, hi, ok = p, p, true
+				continue
+			}
+			lo = Vec2{min(lo.X, p.X), min(lo.Y, p.Y)}
+			hi = Vec2{max(hi.X, p.X), max(hi.Y, p.Y)}
+		}
+	}
+	return lo, hi, ok
+}
@@ -0,0 +1,51 @@
+package physics
+
+import "testing"
+
+func TestTrajectories(t *testing.T) {
+	p, _ := LookupPreset("trisolaris")
+	s := p.New()
+	s.AddDisk(-1, 100, 5, 6, 1)
+	tr := NewTrajectories(s)
+	// 三颗太阳和行星，不含测试粒子盘
+	if len(tr.Bodies) != 4 || tr.Names[3] != "planet" {
+		t.Fatalf("recording %v", tr.Names)
+	}
+	tr.Every = 10
+	integ := &Verlet{}
+	for k := 0; k < 1000; k++ {
+		integ.Step(s, 0.001)
+		tr.Record(s)
+	}
+	if n := len(tr.Paths[0]); n != 101 {
+		t.Errorf("%d points, want 101", n)
+	}
+	if tr.Start != 0 || tr.End != s.Time {
+		t.Errorf("time range %g to %g, want 0 to %g", tr.Start, tr.End, s.Time)
+	}
+	lo, hi, ok := tr.Bounds()
+	if !ok || lo.X > s.Bodies[0].Position.X || hi.X < s.Bodies[0].Position.X {
+		t.Errorf("bounds %v %v do not contain the last position", lo, hi)
+	}
+
+	// 步数不是 Every 的整数倍时，Finish 补上最终位置
+	for k := 0; k < 5; k++ {
+		integ.Step(s, 0.001)
+		tr.Record(s)
+	}
+	tr.Finish(s)
+	if n := len(tr.Paths[0]); n != 102 || tr.Paths[0][n-1] != s.Bodies[0].Position || tr.End != s.Time {
+		t.Errorf("after Finish: %d points ending at t = %g, want 102 ending at the final position", n, tr.End)
+	}
+
+	// 太阳走得很慢，MinStep 大于整段位移时只剩起点
+	tr = NewTrajectories(s)
+	tr.MinStep = 10
+	for k := 0; k < 100; k++ {
+		integ.Step(s, 0.001)
+		tr.Record(s)
+	}
+	if n := len(tr.Paths[0]); n != 1 {
+		t.Errorf("%d points with a large MinStep, want 1", n)
+	}
+}
@@ -0,0 +1,46 @@
+package physics
+
+import "math"
+
+// Vec2 表示二维向量
+type Vec2 struct {
+	X, Y float64
+}
+
+func (v Vec2) Add(other Vec2) Vec2 {
+	return Vec2{v.X + other.X, v.Y + other.Y}
+}
+
+func (v Vec2) Sub(other Vec2) Vec2 {
+	return Vec2{v.X - other.X, v.Y - other.Y}
+}
+
+func (v Vec2) Mult(s float64) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Dot 返回点积
+func (v Vec2) Dot(other Vec2) float64 {
+	return v.X*other.X + v.Y*other.Y
+}
+
+// Cross 返回二维叉积（z 分量）
+func (v Vec2) Cross(other Vec2) float64 {
+	return v.X*other.Y - v.Y*other.X
+}
+
+func (v Vec2) Length() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y)
+}
+
+func (v Vec2) Length2() float64 {
+	return v.X*v.X + v.Y*v.Y
+}
+
+func (v Vec2) Normalize() Vec2 {
+	l := v.Length()
+	if l == 0 {
+		return Vec2{0, 0}
+	}
+	return Vec2{v.X / l, v.Y / l}
+}
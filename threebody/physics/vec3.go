@@ -0,0 +1,67 @@
+package physics
+
+import "math"
+
+// Vec3 表示三维向量
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+func (v Vec3) Add(other Vec3) Vec3 {
+	return Vec3{v.X + other.X, v.Y + other.Y, v.Z + other.Z}
+}
+
+func (v Vec3) Sub(other Vec3) Vec3 {
+	return Vec3{v.X - other.X, v.Y - other.Y, v.Z - other.Z}
+}
+
+func (v Vec3) Mult(s float64) Vec3 {
+	return Vec3{v.X * s, v.Y * s, v.Z * s}
+}
+
+// Dot 返回点积
+func (v Vec3) Dot(other Vec3) float64 {
+	return v.X*other.X + v.Y*other.Y + v.Z*other.Z
+}
+
+// Cross 返回叉积
+func (v Vec3) Cross(other Vec3) Vec3 {
+	return Vec3{
+		v.Y*other.Z - v.Z*other.Y,
+		v.Z*other.X - v.X*other.Z,
+		v.X*other.Y - v.Y*other.X,
+	}
+}
+
+func (v Vec3) Length() float64 {
+	return math.Sqrt(v.Length2())
+}
+
+func (v Vec3) Length2() float64 {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
+}
+
+func (v Vec3) Normalize() Vec3 {
+	l := v.Length()
+	if l == 0 {
+		return Vec3{}
+	}
+	return v.Mult(1 / l)
+}
+
+// XY 返回在 xy 平面上的投影
+func (v Vec3) XY() Vec2 {
+	return Vec2{v.X, v.Y}
+}
+
+// RotateX 绕 x 轴旋转 angle 弧度
+func (v Vec3) RotateX(angle float64) Vec3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return Vec3{v.X, c*v.Y - s*v.Z, s*v.Y + c*v.Z}
+}
+
+// RotateZ 绕 z 轴旋转 angle 弧度
+func (v Vec3) RotateZ(angle float64) Vec3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return Vec3{c*v.X - s*v.Y, s*v.X + c*v.Y, v.Z}
+}
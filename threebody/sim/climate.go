@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+const eraMinDuration = 0.5 // 短于它的纪元并入前一个纪元
+
+var (
+	planetColor     = color.RGBA{120, 200, 255, 255}
+	stableColor     = color.RGBA{60, 180, 90, 255}
+	chaoticColor    = color.RGBA{200, 60, 60, 255}
+	flyingStarColor = color.RGBA{120, 160, 255, 255}
+	multiSunColor   = color.RGBA{255, 220, 80, 255}
+)
+
+// resetClimate 找到（或加入）行星并重新开始纪元记录
+func (g *Game) resetClimate() {
+	g.climate = nil
+	g.timeline = physics.EraTimeline{MinDuration: eraMinDuration}
+	planet := g.sys.Index("planet")
+	if planet < 0 && g.cfg.planet >= 0 {
+		// -planet 数的是有质量天体，不是 Bodies 的下标
+		planet = g.sys.AddPlanet(g.sys.Massive()[g.cfg.planet], g.cfg.planetOrbit, g.cfg.seed)
+	}
+	if planet < 0 {
+		return
+	}
+	m := physics.NewClimateModel(g.sys, planet)
+	g.climate = &m
+	g.updateClimate()
+}
+
+func (g *Game) updateClimate() {
+	if g.climate == nil {
+		return
+	}
+	g.lastClimate = g.climate.Sample(g.sys)
+	g.timeline.Add(g.lastClimate)
+}
+
+func (g *Game) drawPlanet(screen *ebiten.Image) {
+	if g.climate == nil {
+		return
+	}
+	x, y := g.cam.toScreen(g.sys.Bodies[g.climate.Planet].Position)
+	vector.DrawFilledCircle(screen, x, y, 2.5, planetColor, true)
+}
+
+// climateHUD 返回行星气候的状态行
+func (g *Game) climateHUD() string {
+	if g.climate == nil {
+		return ""
+	}
+	c := g.lastClimate
+	msg := fmt.Sprintf("planet: T = %.0f K  suns = %d  %s era", c.Temperature, c.Suns, c.Era)
+	if c.FlyingStar {
+		msg += "  [FLYING STAR]"
+	}
+	if c.MultiSun() {
+		msg += fmt.Sprintf("  [%d SUNS]", c.Suns)
+	}
+	return msg + fmt.Sprintf("  eras: %d\n", len(g.timeline.Eras))
+}
+
+// drawTimeline 在窗口底部画出纪元时间线：绿色恒纪元、红色乱纪元，
+// 上方蓝条表示出现过飞星，下方黄条表示出现过多日凌空。
+func (g *Game) drawTimeline(screen *ebiten.Image) {
+	if g.climate == nil || g.sys.Time <= 0 {
+		return
+	}
+	const (
+		left   = 10
+		width  = screenWidth - 20
+		top    = screenHeight - 20
+		height = 10
+	)
+	scale := width / g.sys.Time
+	for _, e := range g.timeline.Eras {
+		x := float32(left + e.Start*scale)
+		w := float32(e.Duration() * scale)
+		if w < 1 {
+			w = 1
+		}
+		c := chaoticColor
+		if e.Kind == physics.EraStable {
+			c = stableColor
+		}
+		vector.DrawFilledRect(screen, x, top, w, height, c, false)
+		if e.FlyingStar > 0 {
+			vector.DrawFilledRect(screen, x, top-3, w, 2, flyingStarColor, false)
+		}
+		if e.MultiSun > 0 {
+			vector.DrawFilledRect(screen, x, top+height+1, w, 2, multiSunColor, false)
+		}
+	}
+}
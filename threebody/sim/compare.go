@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+var (
+	tileBorder = color.RGBA{60, 60, 80, 255}
+	driftColor = color.RGBA{255, 150, 80, 255}
+)
+
+// tile 是对比模式里的一格：同一个初始条件，不同的积分器或步长
+type tile struct {
+	cam     camera
+	sys     *physics.System
+	integ   physics.Integrator
+	dt      float64
+	energy0 float64
+	trails  [][]physics.Vec2
+
+	times, drifts []float64 // |dE/E0| 随时间的变化
+}
+
+// compareGame 把同一个场景用不同积分器或步长并排运行
+type compareGame struct {
+	cfg    config
+	preset physics.Preset
+	tiles  []*tile
+	target float64 // 各格都推进到的模拟时间，每帧加 -dt×-steps
+
+	paused     bool
+	showTrails bool
+}
+
+// parseCompare 解析 "euler,verlet@0.01,rk4" 形式的列表，@ 后面是步长，省略时用 -dt
+func parseCompare(list string, defaultDT float64) ([]string, []float64, error) {
+	var names []string
+	var dts []float64
+	for _, item := range strings.Split(list, ",") {
+		name, dtText, hasDT := strings.Cut(strings.TrimSpace(item), "@")
+		dt := defaultDT
+		if hasDT {
+			v, err := strconv.ParseFloat(dtText, 64)
+			if err != nil || v <= 0 {
+				return nil, nil, fmt.Errorf("bad step size in %q", item)
+			}
+			dt = v
+		}
+		if _, err := physics.NewIntegrator(name); err != nil {
+			return nil, nil, err
+		}
+		names = append(names, name)
+		dts = append(dts, dt)
+	}
+	return names, dts, nil
+}
+
+func newCompareGame(cfg config) (*compareGame, error) {
+	p, err := lookupPreset(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := p.New()
+	if err := applyForces(s, cfg); err != nil {
+		return nil, err
+	}
+	names, _, err := parseCompare(cfg.compare, cfg.dt)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		integ, _ := newIntegrator(name, cfg)
+		if err := physics.CheckIntegrator(integ, s); err != nil {
+			return nil, err
+		}
+	}
+	g := &compareGame{cfg: cfg, preset: p, showTrails: true}
+	g.Reset()
+	return g, nil
+}
+
+// Reset 重新生成所有格子
+func (g *compareGame) Reset() {
+	names, dts, _ := parseCompare(g.cfg.compare, g.cfg.dt)
+	cols := int(math.Ceil(math.Sqrt(float64(len(names)))))
+	rows := (len(names) + cols - 1) / cols
+	w, h := float64(screenWidth)/float64(cols), float64(screenHeight)/float64(rows)
+	scale := g.cfg.scale / float64(cols)
+	if len(g.tiles) == len(names) {
+		scale = g.tiles[0].cam.scale
+	}
+	g.tiles = nil
+	g.target = g.preset.New().Time
+	for k, name := range names {
+		integ, _ := newIntegrator(name, g.cfg)
+		sys := g.preset.New()
+		applyForces(sys, g.cfg)
+		g.tiles = append(g.tiles, &tile{
+			cam:     newCamera(float64(k%cols)*w, float64(k/cols)*h, w, h, scale),
+			sys:     sys,
+			integ:   integ,
+			dt:      dts[k],
+			energy0: sys.Energy(),
+			trails:  make([][]physics.Vec2, len(sys.Bodies)),
+		})
+	}
+}
+
+func (g *compareGame) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.paused = !g.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.Reset()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		g.showTrails = !g.showTrails
+	}
+	for _, t := range g.tiles {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+			t.cam.scale *= 1.25
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+			t.cam.scale /= 1.25
+		}
+	}
+	if g.paused {
+		return nil
+	}
+
+	// 各格的步长不一定整除每帧的时间，按共同的目标时间推进，格子之间的时间差不超过一步
+	g.target += g.cfg.dt * float64(g.cfg.steps)
+	for _, t := range g.tiles {
+		physics.AdvanceTo(t.sys, t.integ, t.dt, g.target)
+		updateTrails(t.trails, t.sys)
+		t.times, t.drifts = appendPoint(t.times, t.drifts, t.sys.Time, math.Abs(t.drift()))
+	}
+	return nil
+}
+
+// drift 返回相对能量误差 dE/E0
+func (t *tile) drift() float64 {
+	if t.energy0 == 0 {
+		return 0
+	}
+	return (t.sys.Energy() - t.energy0) / math.Abs(t.energy0)
+}
+
+func (g *compareGame) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 20, 255})
+	for _, t := range g.tiles {
+		c := t.cam
+		// 画到子图上，超出格子的部分被裁掉
+		sub := screen.SubImage(image.Rect(int(c.x0), int(c.y0), int(c.x0+c.w), int(c.y0+c.h))).(*ebiten.Image)
+		drawBodies(sub, c, t.sys, t.trails, g.showTrails, func(k int) color.RGBA {
+			return bodyColors[k%len(bodyColors)]
+		})
+		vector.StrokeRect(screen, float32(c.x0), float32(c.y0), float32(c.w), float32(c.h), 1, tileBorder, false)
+		ebitenutil.DebugPrintAt(screen,
+			fmt.Sprintf("%s  dt=%g\nt = %.3f\ndE/E0 = %.2e", t.integ.Name(), t.dt, t.sys.Time, t.drift()),
+			int(c.x0)+6, int(c.y0)+4)
+		plot{
+			x: float32(c.x0 + 6), y: float32(c.y0 + c.h - 86), w: float32(c.w - 12), h: 60,
+			title: "|dE/E0|", logY: true,
+		}.draw(sub, series{xs: t.times, ys: t.drifts, color: driftColor})
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS: %0.1f  [space] pause  [r] reset  [t] trails  [+/-] zoom", ebiten.ActualFPS()),
+		6, screenHeight-16)
+}
+
+func (g *compareGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// grid 是在屏幕上等距采样的标量场，v[j][i] 对应屏幕坐标 (x0+i*cell, y0+j*cell)
+type grid struct {
+	x0, y0, cell float32
+	v            [][]float64
+}
+
+// sampleGrid 在 w×h 的屏幕区域内每隔 cell 像素对 f 采样一次
+func sampleGrid(x0, y0, w, h, cell float32, f func(sx, sy float32) float64) grid {
+	nx, ny := int(w/cell)+1, int(h/cell)+1
+	g := grid{x0: x0, y0: y0, cell: cell, v: make([][]float64, ny)}
+	for j := range g.v {
+		g.v[j] = make([]float64, nx)
+		for i := range g.v[j] {
+			g.v[j][i] = f(x0+float32(i)*cell, y0+float32(j)*cell)
+		}
+	}
+	return g
+}
+
+// drawContour 用 marching squares 画出 level 等值线
+func (g grid) drawContour(dst *ebiten.Image, level float64, c color.Color) {
+	for j := 0; j+1 < len(g.v); j++ {
+		for i := 0; i+1 < len(g.v[j]); i++ {
+			// 四个角按顺时针：左上、右上、右下、左下
+			corners := [4]struct {
+				x, y float32
+				v    float64
+			}{
+				{float32(i), float32(j), g.v[j][i]},
+				{float32(i + 1), float32(j), g.v[j][i+1]},
+				{float32(i + 1), float32(j + 1), g.v[j+1][i+1]},
+				{float32(i), float32(j + 1), g.v[j+1][i]},
+			}
+			var pts [4][2]float32
+			n := 0
+			for k := 0; k < 4; k++ {
+				a, b := corners[k], corners[(k+1)%4]
+				if (a.v < level) == (b.v < level) {
+					continue
+				}
+				t := float32((level - a.v) / (b.v - a.v))
+				pts[n] = [2]float32{
+					g.x0 + (a.x+t*(b.x-a.x))*g.cell,
+					g.y0 + (a.y+t*(b.y-a.y))*g.cell,
+				}
+				n++
+			}
+			// 两个或四个交点，按顺序两两连线（鞍点的歧义不细分）
+			for k := 0; k+1 < n; k += 2 {
+				vector.StrokeLine(dst, pts[k][0], pts[k][1], pts[k+1][0], pts[k+1][1], 1, c, true)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const ellipsePoints = 128
+
+var ellipseColor = color.RGBA{160, 255, 160, 160}
+
+// maxElementsBodies 是找最紧密束缚对的最多有质量天体数，查找要遍历所有天体对
+const maxElementsBodies = 64
+
+// updateElements 每帧找一次最紧密束缚对，画图和 HUD 共用结果
+func (g *Game) updateElements() {
+	g.binaryOK = false
+	if !g.showElements || len(g.sys.Massive()) > maxElementsBodies {
+		return
+	}
+	g.binary, g.binaryOK = g.sys.TightestPair()
+}
+
+// drawElements 画出最紧密束缚对的密切椭圆，焦点在较重的天体上，
+// 并标出近心点
+func (g *Game) drawElements(screen *ebiten.Image) {
+	if !g.binaryOK {
+		return
+	}
+	el := g.binary
+	focus := g.sys.Bodies[el.J].Position
+	pts := el.Ellipse(ellipsePoints)
+	for k := range pts {
+		x0, y0 := g.cam.toScreen(focus.Add(pts[k]))
+		x1, y1 := g.cam.toScreen(focus.Add(pts[(k+1)%len(pts)]))
+		vector.StrokeLine(screen, x0, y0, x1, y1, 1, ellipseColor, true)
+	}
+	x, y := g.cam.toScreen(focus.Add(pts[0]))
+	vector.StrokeCircle(screen, x, y, 4, 1, ellipseColor, true)
+}
+
+// elementsHUD 返回最紧密束缚对的轨道根数
+func (g *Game) elementsHUD() string {
+	if !g.showElements {
+		return ""
+	}
+	if len(g.sys.Massive()) > maxElementsBodies {
+		return fmt.Sprintf("binary: off above %d bodies\n", maxElementsBodies)
+	}
+	if !g.binaryOK {
+		return "binary: none bound\n"
+	}
+	el := g.binary
+	sense := "prograde"
+	if el.Retrograde {
+		sense = "retrograde"
+	}
+	return fmt.Sprintf("binary %s-%s: a = %.4f  e = %.4f  P = %.4f  w = %.1f deg (%s)\n",
+		g.sys.Bodies[el.I].Name, g.sys.Bodies[el.J].Name, el.SemiMajor, el.Eccentricity, el.Period,
+		el.Periapsis*180/math.Pi, sense)
+}
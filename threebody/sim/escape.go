@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"threebody/physics"
+)
+
+// checkEscape 更新三体状态的判定。第一次检测到逃逸时记录日志，
+// 并按 -on-escape 暂停、继续或重置。发生重置时返回 true。
+// Classify 只处理恰好三个有质量天体，其他场景一直是 interacting，不会触发。
+func (g *Game) checkEscape() bool {
+	g.outcome = physics.Classify(g.sys)
+	if !g.outcome.Outcome.Final() || g.escape != nil {
+		return false
+	}
+	c := g.outcome
+	g.escape = &c
+	log.Print(c)
+	switch g.cfg.onEscape {
+	case "stop":
+		g.paused = true
+	case "reset":
+		g.Reset()
+		return true
+	}
+	return false
+}
+
+// outcomeHUD 返回三体状态行
+func (g *Game) outcomeHUD() string {
+	msg := fmt.Sprintf("state: %s\n", g.outcome.Outcome)
+	if g.escape != nil {
+		msg += fmt.Sprintf("escape: body %s at t = %.3f, v_inf = %.4f (%s)\n",
+			g.sys.Bodies[g.escape.Escaper].Name, g.escape.Time, g.escape.EscapeSpeed, g.escape.Outcome)
+	}
+	return msg
+}
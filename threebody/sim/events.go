@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"threebody/physics"
+)
+
+// maxEventBodies 是检测事件的最多有质量天体数。检测要遍历所有天体对，
+// 每步都做，天体再多就比积分本身还慢
+const maxEventBodies = 64
+
+// resetEvents 为新的系统创建事件检测器，接触事件写入日志。
+// -events 0、N 体团或天体太多时不检测，g.events 为 nil
+func (g *Game) resetEvents() {
+	g.events = nil
+	if g.cfg.events <= 0 || g.cfg.cluster != "" || len(g.sys.Massive()) > maxEventBodies {
+		return
+	}
+	g.events = physics.NewEventDetector(g.sys)
+	g.events.Keep = g.cfg.events
+	g.events.On(func(e physics.Event) {
+		if e.Kind == physics.EventContact {
+			log.Print(e)
+		}
+	})
+}
+
+// eventsHUD 返回最近一次最近距离和最近的 -events 个事件
+func (g *Game) eventsHUD() string {
+	if g.events == nil || len(g.events.Events) == 0 {
+		return ""
+	}
+	c := g.events.Closest
+	msg := fmt.Sprintf("closest approach: %s-%s r = %.4g at t = %.3f\n", c.A, c.B, c.Distance, c.Time)
+	recent := g.events.Events[max(0, len(g.events.Events)-g.cfg.events):]
+	for k := len(recent) - 1; k >= 0; k-- {
+		msg += fmt.Sprintf("  %s\n", recent[k])
+	}
+	return msg
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"threebody/physics"
+	"threebody/svgplot"
+)
+
+// resetPaths 从当前状态开始重新记录完整轨迹。
+// 按起始缩放下的一个像素抽稀，几个小时的运行也只占几兆内存。
+func (g *Game) resetPaths() {
+	g.paths = physics.NewTrajectories(g.sys)
+	g.paths.MinStep = 1 / g.cfg.scale
+}
+
+// savePaths 把上次重置以来的完整轨迹写成 SVG
+func (g *Game) savePaths() {
+	f, err := os.Create(g.cfg.svgOut)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer f.Close()
+	g.paths.Finish(g.sys)
+	title := g.cfg.scenarioName() + ", " + g.integ.Name()
+	if err := svgplot.Write(f, g.paths, svgplot.Options{Title: title}); err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("wrote %d trajectories (t = %.4g to %.4g) to %s", len(g.paths.Paths), g.paths.Start, g.paths.End, g.cfg.svgOut)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fieldMode 是背景场的显示方式
+type fieldMode int
+
+const (
+	fieldOff          fieldMode = iota
+	fieldPotential              // 引力势 |Φ|
+	fieldAcceleration           // 加速度大小 |a|
+)
+
+var fieldModeNames = [...]string{"off", "potential", "acceleration"}
+
+func (m fieldMode) String() string {
+	return fieldModeNames[m]
+}
+
+func parseFieldMode(name string) (fieldMode, error) {
+	for k, n := range fieldModeNames {
+		if n == name {
+			return fieldMode(k), nil
+		}
+	}
+	return fieldOff, fmt.Errorf("unknown field mode %q (have %v)", name, fieldModeNames)
+}
+
+const (
+	fieldCell     = 8 // 背景场采样间隔（像素）
+	fieldContours = 10
+)
+
+var fieldContourColor = color.RGBA{255, 255, 255, 70}
+
+// heatStops 是从低到高的热图配色
+var heatStops = []color.RGBA{
+	{0, 0, 4, 255},
+	{40, 11, 84, 255},
+	{101, 21, 110, 255},
+	{159, 42, 99, 255},
+	{212, 72, 66, 255},
+	{245, 125, 21, 255},
+	{250, 193, 39, 255},
+}
+
+// heatColor 把 [0, 1] 的值映射到热图颜色，整体压暗以免盖过天体
+func heatColor(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t)) * float64(len(heatStops)-1)
+	k := min(int(t), len(heatStops)-2)
+	f := t - float64(k)
+	a, b := heatStops[k], heatStops[k+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8((float64(x)*(1-f) + float64(y)*f) * 0.6)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// drawField 在低分辨率网格上重新计算当前时刻的引力势或加速度大小，
+// 取对数后画成热图，并叠加等值线
+func (g *Game) drawField(screen *ebiten.Image) {
+	if g.field == fieldOff {
+		return
+	}
+	field := sampleGrid(0, 0, screenWidth, screenHeight, fieldCell, func(sx, sy float32) float64 {
+		p := g.cam.toWorld(sx, sy)
+		if g.field == fieldPotential {
+			return math.Log10(-g.sys.PotentialAt(p))
+		}
+		return math.Log10(g.sys.FieldAt(p).Length())
+	})
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, row := range field.v {
+		for _, v := range row {
+			if !math.IsInf(v, 0) && !math.IsNaN(v) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+	if !(hi > lo) {
+		return
+	}
+
+	ny, nx := len(field.v), len(field.v[0])
+	if g.fieldImage == nil || g.fieldImage.Bounds().Dx() != nx || g.fieldImage.Bounds().Dy() != ny {
+		g.fieldImage = ebiten.NewImage(nx, ny)
+		g.fieldPixels = make([]byte, 4*nx*ny)
+	}
+	for j, row := range field.v {
+		for i, v := range row {
+			c := heatColor((v - lo) / (hi - lo))
+			k := 4 * (j*nx + i)
+			g.fieldPixels[k], g.fieldPixels[k+1], g.fieldPixels[k+2], g.fieldPixels[k+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	g.fieldImage.WritePixels(g.fieldPixels)
+
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(fieldCell, fieldCell)
+	op.GeoM.Translate(-fieldCell/2, -fieldCell/2)
+	screen.DrawImage(g.fieldImage, op)
+
+	for k := 1; k < fieldContours; k++ {
+		field.drawContour(screen, lo+(hi-lo)*float64(k)/fieldContours, fieldContourColor)
+	}
+}
+
+// nextField 在关闭、引力势、加速度之间切换
+func (g *Game) nextField() {
+	g.field = (g.field + 1) % fieldMode(len(fieldModeNames))
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+const (
+	trailLength = 30 * 60 // 30 秒的尾迹（按 60 帧每秒）
+	minRadius   = 3.0     // 天体最小显示半径（像素）
+)
+
+var bodyColors = []color.RGBA{
+	{255, 100, 100, 255},
+	{100, 255, 100, 255},
+	{100, 100, 255, 255},
+	{255, 220, 100, 255},
+	{220, 100, 255, 255},
+	{100, 230, 230, 255},
+}
+
+var particleColor = color.RGBA{200, 200, 200, 160}
+
+// Game 表示模拟窗口的状态
+type Game struct {
+	cfg     config
+	sys     *physics.System
+	integ   physics.Integrator
+	energy0 float64
+	cam     camera
+	trails  [][]physics.Vec2
+
+	paused     bool
+	showTrails bool
+
+	climate     *physics.ClimateModel // 没有行星时为 nil
+	timeline    physics.EraTimeline
+	lastClimate physics.ClimateSample
+
+	outcome physics.Classification  // 当前的三体状态
+	escape  *physics.Classification // 第一次检测到的逃逸，没有时为 nil
+
+	lyapunov *physics.Lyapunov // 未开启时为 nil
+
+	twins []*twin // 带扰动的副本，不开启时为空
+
+	field       fieldMode // 背景场
+	fieldImage  *ebiten.Image
+	fieldPixels []byte
+
+	showVelocity     bool
+	showAcceleration bool
+	showPairs        bool
+	pos, vel, acc    []physics.Vec2 // 画加速度箭头用的缓冲
+
+	events *physics.EventDetector
+
+	showElements bool             // 显示最紧密束缚对的轨道根数和密切椭圆
+	binary       physics.Elements // 本帧的最紧密束缚对，见 updateElements
+	binaryOK     bool
+
+	reverse *reversal
+
+	showGlow bool // 用加色混合的点精灵画测试粒子
+	glow     particleGlow
+
+	paths *physics.Trajectories // 上次重置以来的完整轨迹，按 E 导出
+}
+
+// NewGame 按配置创建模拟
+func NewGame(cfg config) (*Game, error) {
+	p, err := lookupPreset(cfg)
+	if err != nil {
+		return nil, err
+	}
+	integ, err := newIntegrator(cfg.integrator, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := p.New()
+	if err := applyForces(s, cfg); err != nil {
+		return nil, err
+	}
+	if err := physics.CheckIntegrator(integ, s); err != nil {
+		return nil, err
+	}
+	// 逃逸只对恰好三个有质量天体判定，其他场景里 -on-escape 不会触发
+	if n := len(s.Massive()); n != 3 {
+		if err := rejectFlags(fmt.Sprintf("%s with %d massive bodies (escapes are detected for three only)", p.Name, n), "on-escape"); err != nil {
+			return nil, err
+		}
+	}
+	if n := len(p.New().Massive()); cfg.planet >= n {
+		return nil, fmt.Errorf("planet host %d out of range, preset %s has %d suns", cfg.planet, p.Name, n)
+	}
+	if cfg.planet >= 0 && cfg.planetOrbit <= 0 {
+		return nil, fmt.Errorf("planet orbit must be positive, got %g", cfg.planetOrbit)
+	}
+	if cfg.twins == 1 || cfg.twins < 0 {
+		return nil, fmt.Errorf("-twins needs at least 2 copies, got %d", cfg.twins)
+	}
+	field, err := parseFieldMode(cfg.field)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.onEscape {
+	case "stop", "continue", "reset":
+	default:
+		return nil, fmt.Errorf("unknown -on-escape action %q", cfg.onEscape)
+	}
+	g := &Game{
+		cfg:          cfg,
+		integ:        integ,
+		cam:          newCamera(0, 0, screenWidth, screenHeight, cfg.scale),
+		showTrails:   cfg.cluster == "", // 上百颗星的尾迹太乱也太慢，N 体团默认不画，事件和轨道根数也一样
+		field:        field,
+		showElements: cfg.elements && cfg.cluster == "" && len(p.New().Massive()) <= maxElementsBodies,
+		showGlow:     cfg.glow,
+	}
+	g.Reset()
+	return g, nil
+}
+
+// Reset 重新生成初始条件
+func (g *Game) Reset() {
+	p, _ := lookupPreset(g.cfg)
+	g.sys = p.New()
+	applyForces(g.sys, g.cfg)
+	if g.cfg.particles > 0 {
+		g.sys.AddDisk(g.cfg.diskCenter, g.cfg.particles, g.cfg.diskRMin, g.cfg.diskRMax, g.cfg.seed)
+	}
+	g.resetClimate()
+	g.outcome = physics.Classify(g.sys)
+	g.escape = nil
+	g.resetEvents()
+	g.resetReverse()
+	if g.lyapunov != nil || g.cfg.lyapunov {
+		g.lyapunov = nil
+		g.toggleLyapunov()
+	}
+	g.energy0 = g.sys.Energy()
+	g.trails = make([][]physics.Vec2, len(g.sys.Bodies))
+	g.resetPaths()
+	g.resetTwins()
+}
+
+func (g *Game) handleInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.paused = !g.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.Reset()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		g.showTrails = !g.showTrails
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		g.nextField()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyV) {
+		g.showVelocity = !g.showVelocity
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		g.showAcceleration = !g.showAcceleration
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		g.showPairs = !g.showPairs
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		g.showElements = !g.showElements
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		g.showGlow = !g.showGlow
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		g.toggleLyapunov()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		g.savePaths()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.cam.scale *= 1.25
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.cam.scale /= 1.25
+	}
+}
+
+func (g *Game) Update() error {
+	g.handleInput()
+	// 不论暂停、逃逸还是正常推进，每帧结束时都更新一次束缚对
+	defer g.updateElements()
+	if g.paused {
+		return nil
+	}
+
+	for k := 0; k < g.cfg.steps; k++ {
+		g.integ.Step(g.sys, g.cfg.dt)
+		if g.events != nil {
+			g.events.Check(g.sys)
+		}
+		if g.sys.WrapPeriodic() && g.events != nil {
+			g.events.Resync(g.sys)
+		}
+		if g.lyapunov != nil {
+			g.lyapunov.Advance(g.sys, g.cfg.dt)
+		}
+		for _, t := range g.twins {
+			t.integ.Step(t.sys, g.cfg.dt)
+			t.sys.WrapPeriodic()
+		}
+		if g.stepReverse() {
+			break
+		}
+	}
+
+	if g.checkEscape() {
+		return nil
+	}
+	g.updateClimate()
+
+	updateTrails(g.trails, g.sys)
+	g.paths.Record(g.sys)
+	g.updateTwins()
+	return nil
+}
+
+// updateTrails 把有质量天体的当前位置追加到尾迹
+func updateTrails(trails [][]physics.Vec2, sys *physics.System) {
+	for _, i := range sys.Massive() {
+		trails[i] = append(trails[i], sys.Bodies[i].Position)
+		if len(trails[i]) > trailLength {
+			trails[i] = trails[i][1:]
+		}
+	}
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 20, 255})
+	g.drawField(screen)
+
+	// 测试粒子
+	if g.showGlow {
+		g.glow.draw(screen, g.cam, g.sys)
+	} else {
+		for i := range g.sys.Bodies {
+			b := &g.sys.Bodies[i]
+			if !b.IsTest() || b.Name == "planet" {
+				continue
+			}
+			x, y := g.cam.toScreen(b.Position)
+			vector.DrawFilledRect(screen, x, y, 1, 1, particleColor, false)
+		}
+	}
+
+	if len(g.twins) > 0 {
+		g.drawTwins(screen)
+	} else {
+		drawBodies(screen, g.cam, g.sys, g.trails, g.showTrails, func(k int) color.RGBA {
+			return bodyColors[k%len(bodyColors)]
+		})
+	}
+	g.drawPlanet(screen)
+	g.drawElements(screen)
+	g.drawVectors(screen)
+
+	g.drawTimeline(screen)
+	g.drawLyapunov(screen)
+	g.drawHUD(screen)
+}
+
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	e := g.sys.Energy()
+	drift := 0.0
+	if g.energy0 != 0 {
+		drift = (e - g.energy0) / math.Abs(g.energy0)
+	}
+	msg := fmt.Sprintf("preset: %s  integrator: %s  dt: %g\n", g.cfg.scenarioName(), g.integ.Name(), g.cfg.dt)
+	msg += fmt.Sprintf("t = %.3f  E = %.6f  dE/E0 = %.2e\n", g.sys.Time, e, drift)
+	integ := g.integ
+	if m, ok := integ.(physics.MassVarying); ok {
+		integ = m.Unwrap()
+	}
+	if r, ok := integ.(*physics.Regularized); ok && r.Pair[0] >= 0 {
+		msg += fmt.Sprintf("regularized pair: %s-%s\n", g.sys.Bodies[r.Pair[0]].Name, g.sys.Bodies[r.Pair[1]].Name)
+	}
+	if g.cfg.forces != "" {
+		msg += fmt.Sprintf("forces: %s\n", g.cfg.forces)
+	}
+	msg += g.massLossHUD()
+	if n := g.sys.TestParticles(); n > 0 {
+		msg += fmt.Sprintf("test particles: %d\n", n)
+	}
+	if g.field != fieldOff {
+		msg += fmt.Sprintf("field: log10 %s\n", g.field)
+	}
+	msg += g.outcomeHUD()
+	msg += g.reverseHUD()
+	msg += g.eventsHUD()
+	msg += g.elementsHUD()
+	msg += g.climateHUD()
+	msg += g.lyapunovHUD()
+	msg += g.twinsHUD()
+	msg += fmt.Sprintf("FPS: %0.1f  [space] pause  [r] reset  [t] trails  [f] field  [l] lyapunov  [+/-] zoom\n", ebiten.ActualFPS())
+	msg += "[v] velocity  [a] acceleration  [c] pairwise gravity  [o] orbital elements  [g] glow  [e] export svg"
+	ebitenutil.DebugPrint(screen, msg)
+}
+
+// massLossHUD 列出质量在变化的天体和它们当前的质量
+func (g *Game) massLossHUD() string {
+	if len(g.sys.MassLoss) == 0 {
+		return ""
+	}
+	msg := "mass:"
+	for _, l := range g.sys.MassLoss {
+		b := &g.sys.Bodies[l.Body]
+		msg += fmt.Sprintf("  %s %.4g (%s)", b.Name, b.Mass, l.Law.Name())
+	}
+	return msg + "\n"
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+const (
+	glowSize  = 5   // 点精灵的边长（像素）
+	glowCell  = 4   // 统计密度的网格边长（像素）
+	glowAlpha = 0.5 // 单个粒子的亮度，重叠时加色混合叠加
+)
+
+// glowSprite 是中心亮、边缘渐暗的白色点精灵
+var glowSprite = func() *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, glowSize, glowSize))
+	c := float64(glowSize-1) / 2
+	for y := 0; y < glowSize; y++ {
+		for x := 0; x < glowSize; x++ {
+			d := math.Hypot(float64(x)-c, float64(y)-c) / (c + 0.5)
+			a := uint8(255 * math.Max(0, 1-d*d))
+			img.SetRGBA(x, y, color.RGBA{a, a, a, a})
+		}
+	}
+	return ebiten.NewImageFromImage(img)
+}()
+
+// glowStops 是密度从低到高对应的颜色
+var glowStops = []color.RGBA{
+	{50, 80, 200, 255},   // 稀疏：暗蓝
+	{120, 160, 255, 255}, // 浅蓝
+	{255, 190, 110, 255}, // 橙
+	{255, 250, 235, 255}, // 最密：近白
+}
+
+// glowColor 把 [0, 1] 的相对密度插值成颜色
+func glowColor(t float64) (r, g, b float32) {
+	t = math.Max(0, math.Min(1, t)) * float64(len(glowStops)-1)
+	k := min(int(t), len(glowStops)-2)
+	f := float32(t - float64(k))
+	c0, c1 := glowStops[k], glowStops[k+1]
+	lerp := func(a, b uint8) float32 { return (float32(a)*(1-f) + float32(b)*f) / 255 }
+	return lerp(c0.R, c1.R), lerp(c0.G, c1.G), lerp(c0.B, c1.B)
+}
+
+// particleGlow 用加色混合的点精灵画测试粒子，每个粒子的颜色取决于
+// 它所在网格单元里的粒子数：密集的星系核心发白，稀疏的潮汐尾发蓝
+type particleGlow struct {
+	counts []int
+	cells  []int // 每个粒子所在的网格单元，屏幕外为 -1
+}
+
+func (pg *particleGlow) draw(screen *ebiten.Image, cam camera, sys *physics.System) {
+	cols, rows := int(cam.w)/glowCell+1, int(cam.h)/glowCell+1
+	if len(pg.counts) != cols*rows {
+		pg.counts = make([]int, cols*rows)
+	}
+	clear(pg.counts)
+	pg.cells = pg.cells[:0]
+	for i := range sys.Bodies {
+		b := &sys.Bodies[i]
+		cell := -1
+		if b.IsTest() && b.Name != "planet" {
+			x, y := cam.toScreen(b.Position)
+			cx, cy := int((float64(x)-cam.x0)/glowCell), int((float64(y)-cam.y0)/glowCell)
+			if cx >= 0 && cx < cols && cy >= 0 && cy < rows {
+				cell = cy*cols + cx
+				pg.counts[cell]++
+			}
+		}
+		pg.cells = append(pg.cells, cell)
+	}
+
+	var op ebiten.DrawImageOptions
+	op.Blend = ebiten.BlendLighter
+	for i, cell := range pg.cells {
+		if cell < 0 {
+			continue
+		}
+		// 一个单元里有 1 个粒子时最暗，32 个以上时最亮
+		r, g, b := glowColor(math.Log2(float64(pg.counts[cell])) / 5)
+		x, y := cam.toScreen(sys.Bodies[i].Position)
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64(x)-glowSize/2, float64(y)-glowSize/2)
+		op.ColorScale.Reset()
+		op.ColorScale.Scale(r*glowAlpha, g*glowAlpha, b*glowAlpha, glowAlpha)
+		screen.DrawImage(glowSprite, &op)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+const (
+	lyapunovD0       = 1e-8 // 影子轨道的初始相空间偏离
+	lyapunovInterval = 0.1  // 重归一化间隔（模拟时间）
+)
+
+var lyapunovColor = color.RGBA{255, 180, 80, 255}
+
+// toggleLyapunov 打开或关闭李雅普诺夫指数估计，打开时从当前状态开始
+func (g *Game) toggleLyapunov() {
+	if g.lyapunov != nil {
+		g.lyapunov = nil
+		return
+	}
+	l, err := physics.NewLyapunov(g.sys, g.integ, lyapunovD0, lyapunovInterval)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	g.lyapunov = l
+}
+
+func (g *Game) lyapunovHUD() string {
+	if g.lyapunov == nil {
+		return ""
+	}
+	return fmt.Sprintf("lyapunov: lambda = %.4f  (%d renormalizations)\n",
+		g.lyapunov.Exponent(), g.lyapunov.Renormalizations)
+}
+
+// drawLyapunov 在右上角画出指数估计随时间的变化
+func (g *Game) drawLyapunov(screen *ebiten.Image) {
+	if g.lyapunov == nil {
+		return
+	}
+	l := series{color: lyapunovColor}
+	for _, s := range g.lyapunov.Samples {
+		l.xs = append(l.xs, s.Time)
+		l.ys = append(l.ys, s.Exponent)
+	}
+	plot{x: screenWidth - 250, y: 10, w: 240, h: 100, title: "lambda(t)"}.draw(screen, l)
+}
@@ -0,0 +1,222 @@
+// sim 是基于 physics 包的三体模拟窗口。
+//
+//	go run ./sim -preset figure8 -integrator verlet
+//	go run ./sim -preset hierarchical -particles 3000 -disk-center 0 -disk-rmin 0.05 -disk-rmax 0.15
+//	go run ./sim -preset trisolaris -scale 60
+//	go run ./sim -preset pythagorean -scale 60 -twins 3 -perturb 1e-9
+//	go run ./sim -preset figure8 -compare euler,verlet,rk4,adaptive -dt 0.01 -steps 1
+//	go run ./sim -preset lagrange -forces "gravity;coulomb:1;drag:0.02" -charges 0.5,-0.5,0.5
+//	go run ./sim -preset hierarchical -mass-loss "A:sudden:5,0.7" -scale 80
+//	go run ./sim -preset mercury -integrator rk4 -scale 250
+//	go run ./sim -preset pythagorean -integrator regularized -scale 60
+//	go run ./sim -preset figure8 -integrator euler -reverse 5
+//	go run ./sim -preset pythagorean -compare verlet,rk4-big@0.0005 -dt 0.002
+//	go run ./sim -3d -preset kozai -integrator rk4 -dt 0.01 -scale 60
+//	go run ./sim -cluster plummer:500 -softening 0.02 -dt 0.005 -steps 2 -scale 150
+//	go run ./sim -3d -cluster disk:800 -softening 0.01 -integrator rk4 -dt 0.002 -scale 120
+//	go run ./sim -cluster collapse:3000 -forces pm:4,128 -softening 0.1 -dt 0.005 -steps 2 -scale 140
+//	go run ./sim -preset galaxies -dt 0.005 -scale 60
+//	go run ./sim -preset pythagorean -integrator rk4 -svg-out pythagorean.svg
+//	go run ./sim -restricted horseshoe -mu 0.001 -dt 0.005 -scale 250
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+const (
+	screenWidth  = 800
+	screenHeight = 600
+)
+
+// config 是命令行参数
+type config struct {
+	preset     string
+	cluster    string // N 体团生成器，见 physics.ParseCluster，非空时代替 preset
+	integrator string
+	dt         float64
+	steps      int // 每帧积分步数
+	softening  float64
+	forces     string  // 力的列表，见 physics.ParseForces，空表示只有引力
+	charges    string  // 按天体顺序的电荷，逗号分隔
+	massLoss   string  // 质量随时间的变化，见 physics.System.SetMassLoss
+	scale      float64 // 每个模拟单位对应的像素数
+
+	particles  int // 测试粒子个数
+	diskCenter int // 测试粒子盘围绕的天体下标，-1 表示质心
+	diskRMin   float64
+	diskRMax   float64
+	seed       int64
+	glow       bool // 用加色混合的点精灵按密度着色画测试粒子
+
+	planet      int     // 行星围绕第几个有质量天体，-1 表示不加行星
+	planetOrbit float64 // 行星初始轨道半径
+
+	onEscape string // 检测到逃逸后的动作：stop、continue 或 reset
+	events   int    // HUD 显示最近的事件个数，0 表示不检测
+	elements bool   // 启动时显示最紧密束缚对的轨道根数
+	lyapunov bool   // 启动时就开启李雅普诺夫指数估计
+
+	twins   int     // 同时运行的副本个数（含原始场景），0 表示不开启
+	perturb float64 // 第 k 个副本第一个天体的 x 坐标偏移 k×perturb
+
+	compare string // 对比模式的积分器列表，如 "euler,verlet,rk4,adaptive"，空表示不开启
+
+	restricted string  // 限制性三体模式的第三体初始轨道，空表示不开启
+	mu         float64 // 限制性三体的质量比
+	section    string  // 限制性三体模式的庞加莱截面，auto 按轨道选择，空表示不记录
+	sectionOut string  // 按 S 保存截面点的文件
+
+	field string // 背景场：off、potential 或 acceleration
+
+	svgOut string // 按 E 导出完整轨迹的 SVG 文件
+
+	threeD bool // 三维模式
+
+	reverse float64 // 时间可逆性检查的单程时间，0 表示不开启
+
+	velScale float64 // 速度箭头长度 = 速度 × velScale（模拟单位）
+	accScale float64 // 加速度箭头长度 = 加速度 × accScale
+}
+
+func parseFlags() config {
+	var c config
+	flag.StringVar(&c.preset, "preset", "figure8", "initial condition preset")
+	flag.StringVar(&c.cluster, "cluster", "", "generate an N-body cluster instead of the preset: plummer, disk or collapse, optionally with :N[,seed]; turns off trails, events and orbital elements")
+	flag.StringVar(&c.integrator, "integrator", "verlet", "integrator name")
+	flag.Float64Var(&c.dt, "dt", 0.001, "time step in simulation units")
+	flag.IntVar(&c.steps, "steps", 10, "integration steps per frame")
+	flag.Float64Var(&c.softening, "softening", 0, "gravitational softening length")
+	flag.StringVar(&c.forces, "forces", "", "force laws separated by ';', e.g. gravity;coulomb:1;spring:A,B,5,1;drag:0.1;field:0,-1 (empty = gravity only)")
+	flag.StringVar(&c.charges, "charges", "", "comma-separated charges assigned to bodies in order")
+	flag.StringVar(&c.massLoss, "mass-loss", "", "time-varying masses separated by ';', e.g. A:exp:50;C:sudden:20,0.6 (laws: constant, linear, exp, sudden)")
+	flag.Float64Var(&c.scale, "scale", 200, "pixels per simulation unit")
+	flag.IntVar(&c.particles, "particles", 0, "number of massless test particles")
+	flag.IntVar(&c.diskCenter, "disk-center", -1, "body the test-particle disk orbits (-1 = center of mass)")
+	flag.Float64Var(&c.diskRMin, "disk-rmin", 1.5, "inner radius of the test-particle disk")
+	flag.Float64Var(&c.diskRMax, "disk-rmax", 3, "outer radius of the test-particle disk")
+	flag.Int64Var(&c.seed, "seed", 1, "random seed for generated particles")
+	flag.BoolVar(&c.glow, "glow", true, "draw test particles as additive glowing sprites colored by density (toggle with G)")
+	flag.IntVar(&c.planet, "planet", -1, "add a habitable planet around this sun, counting massive bodies only (-1 = none; presets may bring their own)")
+	flag.Float64Var(&c.planetOrbit, "planet-orbit", 0.15, "initial orbit radius of the planet")
+	flag.StringVar(&c.onEscape, "on-escape", "stop", "what to do when a body escapes: stop, continue or reset (only presets with exactly three massive bodies are checked)")
+	flag.IntVar(&c.events, "events", 3, "detect pericenter/apocenter/contact events and show this many in the HUD (0 = off; always off above 64 massive bodies)")
+	flag.BoolVar(&c.elements, "elements", true, "show orbital elements and the osculating ellipse of the tightest bound pair (off above 64 massive bodies)")
+	flag.BoolVar(&c.lyapunov, "lyapunov", false, "estimate the maximal Lyapunov exponent from the start")
+	flag.IntVar(&c.twins, "twins", 0, "run this many copies of the scenario overlaid, each slightly perturbed (0 = off)")
+	flag.Float64Var(&c.perturb, "perturb", 1e-9, "x offset of the first body in the k-th twin is k times this")
+	flag.StringVar(&c.compare, "compare", "", "run the preset side by side with these integrators, e.g. euler,verlet@0.01,rk4,adaptive")
+	flag.StringVar(&c.restricted, "restricted", "", "circular restricted three-body mode; third body on a tadpole, horseshoe or radius,angle orbit")
+	flag.Float64Var(&c.mu, "mu", 0.001, "mass ratio of the secondary in restricted mode")
+	flag.StringVar(&c.section, "section", "auto", "Poincare surface of section recorded in restricted mode, e.g. y=0,vy>0 (auto = through L4 for tadpole, y=0,vy>0 otherwise; empty = off)")
+	flag.StringVar(&c.sectionOut, "section-out", "poincare.csv", "file written when pressing S in restricted mode")
+	flag.StringVar(&c.svgOut, "svg-out", "trajectories.svg", "file written when pressing E: full trajectories since the last reset as SVG")
+	flag.StringVar(&c.field, "field", "off", "background layer: off, potential or acceleration")
+	flag.Float64Var(&c.reverse, "reverse", 0, "integrate forward this long, flip velocities, integrate back and report the distance to the start (0 = off)")
+	flag.BoolVar(&c.threeD, "3d", false, "3D mode with plain gravity; 3D presets (kozai, tilted-figure8) or any gravity-only 2D preset tilted out of the plane")
+	flag.Float64Var(&c.velScale, "vel-scale", 0.2, "velocity arrow length per unit speed")
+	flag.Float64Var(&c.accScale, "acc-scale", 0.05, "acceleration arrow length per unit acceleration")
+	flag.Parse()
+	return c
+}
+
+func main() {
+	cfg := parseFlags()
+	var (
+		game ebiten.Game
+		err  error
+	)
+	if cfg.threeD && (cfg.compare != "" || cfg.restricted != "") {
+		log.Fatal("-3d cannot be combined with -compare or -restricted")
+	}
+	switch {
+	case cfg.compare != "":
+		game, err = newCompareGame(cfg)
+	case cfg.restricted != "":
+		game, err = newRestrictedGame(cfg)
+	case cfg.threeD:
+		game, err = newGame3D(cfg)
+	default:
+		game, err = NewGame(cfg)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("三体问题模拟")
+	if err := ebiten.RunGame(game); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// rejectFlags 在命令行显式给出了 names 里的参数时报错，mode 是不支持它们的模式
+func rejectFlags(mode string, names ...string) error {
+	var set []string
+	flag.Visit(func(f *flag.Flag) {
+		if slices.Contains(names, f.Name) {
+			set = append(set, "-"+f.Name)
+		}
+	})
+	if len(set) > 0 {
+		return fmt.Errorf("%s does not support %s", mode, strings.Join(set, ", "))
+	}
+	return nil
+}
+
+// scenarioName 是 HUD 里显示的场景名
+func (c config) scenarioName() string {
+	if c.cluster != "" {
+		return c.cluster
+	}
+	return c.preset
+}
+
+// lookupPreset 返回 -cluster 生成的 N 体团，没有时返回 -preset 指定的场景
+func lookupPreset(c config) (physics.Preset, error) {
+	if c.cluster != "" {
+		return physics.ParseCluster(c.cluster)
+	}
+	return physics.LookupPreset(c.preset)
+}
+
+// lookupPreset3 是 lookupPreset 的三维版本
+func lookupPreset3(c config) (physics.Preset3, error) {
+	if c.cluster != "" {
+		return physics.ParseCluster3(c.cluster)
+	}
+	return physics.LookupPreset3(c.preset)
+}
+
+// newIntegrator 按名字创建积分器，有 -mass-loss 时包装成 MassVarying
+func newIntegrator(name string, c config) (physics.Integrator, error) {
+	integ, err := physics.NewIntegrator(name)
+	if err != nil || c.massLoss == "" {
+		return integ, err
+	}
+	return physics.MassVarying{Integrator: integ}, nil
+}
+
+// applyForces 把 -softening、-forces、-charges 和 -mass-loss 应用到新建的系统上
+func applyForces(s *physics.System, c config) error {
+	s.Softening = c.softening
+	if c.forces != "" {
+		laws, err := physics.ParseForces(c.forces)
+		if err != nil {
+			return err
+		}
+		s.Forces = laws
+	}
+	if err := s.SetCharges(c.charges); err != nil {
+		return err
+	}
+	return s.SetMassLoss(c.massLoss)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var (
+	plotBackground = color.RGBA{0, 0, 0, 180}
+	plotFrame      = color.RGBA{90, 90, 110, 255}
+)
+
+// series 是图中的一条折线，dots 为真时只画散点
+type series struct {
+	xs, ys []float64
+	color  color.RGBA
+	dots   bool
+}
+
+// maxPlotPoints 是实时曲线保留的最多点数，比图表的像素宽度多几倍就够了
+const maxPlotPoints = 1000
+
+// appendPoint 给曲线追加一点，超过 maxPlotPoints 时隔一个丢一个：
+// 保留整段时间但分辨率减半，最后一点总是最新的。内存和绘制开销不随运行时间增长
+func appendPoint(xs, ys []float64, x, y float64) ([]float64, []float64) {
+	xs, ys = append(xs, x), append(ys, y)
+	if len(xs) <= maxPlotPoints {
+		return xs, ys
+	}
+	n := 0
+	for k := 0; k < len(xs)-1; k += 2 {
+		xs[n], ys[n] = xs[k], ys[k]
+		n++
+	}
+	return append(xs[:n], x), append(ys[:n], y)
+}
+
+// plot 是窗口里的一个小图表，纵轴范围按数据自动缩放
+type plot struct {
+	x, y, w, h float32
+	title      string
+	logY       bool // 纵轴取以 10 为底的对数
+}
+
+func (p plot) draw(screen *ebiten.Image, lines ...series) {
+	vector.DrawFilledRect(screen, p.x, p.y, p.w, p.h, plotBackground, false)
+	vector.StrokeRect(screen, p.x, p.y, p.w, p.h, 1, plotFrame, false)
+
+	xmin, xmax := math.Inf(1), math.Inf(-1)
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	for _, l := range lines {
+		for k := range l.xs {
+			y := p.value(l.ys[k])
+			if math.IsInf(y, 0) || math.IsNaN(y) {
+				continue
+			}
+			xmin, xmax = math.Min(xmin, l.xs[k]), math.Max(xmax, l.xs[k])
+			ymin, ymax = math.Min(ymin, y), math.Max(ymax, y)
+		}
+	}
+	if xmin >= xmax || math.IsInf(ymin, 0) {
+		ebitenutil.DebugPrintAt(screen, p.title, int(p.x)+4, int(p.y)+2)
+		return
+	}
+	if ymin == ymax {
+		ymin, ymax = ymin-1, ymax+1
+	}
+
+	toScreen := func(x, y float64) (float32, float32) {
+		sx := p.x + float32((x-xmin)/(xmax-xmin))*p.w
+		sy := p.y + p.h - float32((y-ymin)/(ymax-ymin))*p.h
+		return sx, sy
+	}
+	for _, l := range lines {
+		if l.dots {
+			for k := range l.xs {
+				if y := p.value(l.ys[k]); !math.IsInf(y, 0) && !math.IsNaN(y) {
+					x, sy := toScreen(l.xs[k], y)
+					vector.DrawFilledRect(screen, x, sy, 1, 1, l.color, false)
+				}
+			}
+			continue
+		}
+		for k := 1; k < len(l.xs); k++ {
+			y0, y1 := p.value(l.ys[k-1]), p.value(l.ys[k])
+			if math.IsInf(y0, 0) || math.IsInf(y1, 0) || math.IsNaN(y0) || math.IsNaN(y1) {
+				continue
+			}
+			x0, sy0 := toScreen(l.xs[k-1], y0)
+			x1, sy1 := toScreen(l.xs[k], y1)
+			vector.StrokeLine(screen, x0, sy0, x1, sy1, 1, l.color, false)
+		}
+	}
+
+	label := fmt.Sprintf("%s  [%.3g, %.3g]", p.title, ymin, ymax)
+	if p.logY {
+		label = fmt.Sprintf("%s  [1e%.1f, 1e%.1f]", p.title, ymin, ymax)
+	}
+	ebitenutil.DebugPrintAt(screen, label, int(p.x)+4, int(p.y)+2)
+}
+
+func (p plot) value(y float64) float64 {
+	if p.logY {
+		return math.Log10(y)
+	}
+	return y
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/csv"
+	"image/color"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+var sectionColor = color.RGBA{140, 230, 255, 255}
+
+// resetSections 为每个粒子新建一个庞加莱截面
+func (g *restrictedGame) resetSections() {
+	g.sections = nil
+	if g.cfg.section == "" {
+		return
+	}
+	for range g.sys.Particles {
+		sec, _ := physics.ParseSection(g.cfg.section)
+		g.sections = append(g.sections, sec)
+	}
+}
+
+// updateSections 在每一步之后检查穿越
+func (g *restrictedGame) updateSections() {
+	for k, sec := range g.sections {
+		sec.Update(g.sys.Time, &g.sys.Particles[k])
+	}
+}
+
+// drawSections 在右上角画出截面上的 (q, p) 散点
+func (g *restrictedGame) drawSections(screen *ebiten.Image) {
+	if len(g.sections) == 0 {
+		return
+	}
+	var pts series
+	pts.color, pts.dots = sectionColor, true
+	for _, sec := range g.sections {
+		for _, p := range sec.Points {
+			pts.xs = append(pts.xs, p.Q)
+			pts.ys = append(pts.ys, p.P)
+		}
+	}
+	sec := g.sections[0]
+	q := "x"
+	if sec.Axis == 'x' {
+		q = "y"
+	}
+	plot{
+		x: screenWidth - 266, y: 10, w: 256, h: 256,
+		title: "section " + sec.String() + ": v" + q + " vs " + q,
+	}.draw(screen, pts)
+}
+
+// saveSections 把所有截面点写成 CSV
+func (g *restrictedGame) saveSections() {
+	f, err := os.Create(g.cfg.sectionOut)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"particle", "time", "q", "p"})
+	n := 0
+	for k, sec := range g.sections {
+		for _, p := range sec.Points {
+			w.Write([]string{strconv.Itoa(k), ftoa(p.Time), ftoa(p.Q), ftoa(p.P)})
+			n++
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("wrote %d section points to %s", n, g.cfg.sectionOut)
+}
+
+func ftoa(x float64) string {
+	return strconv.FormatFloat(x, 'g', 8, 64)
+}
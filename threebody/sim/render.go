@@ -0,0 +1,85 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+// camera 把模拟坐标映射到屏幕上一个矩形视口（y 轴向上）
+type camera struct {
+	center physics.Vec2
+	scale  float64 // 每个模拟单位对应的像素数
+
+	x0, y0, w, h float64 // 视口在屏幕上的位置和大小
+}
+
+func newCamera(x0, y0, w, h, scale float64) camera {
+	return camera{scale: scale, x0: x0, y0: y0, w: w, h: h}
+}
+
+func (c camera) toScreen(p physics.Vec2) (float32, float32) {
+	x := c.x0 + c.w/2 + (p.X-c.center.X)*c.scale
+	y := c.y0 + c.h/2 - (p.Y-c.center.Y)*c.scale
+	return float32(x), float32(y)
+}
+
+// toWorld 是 toScreen 的逆变换
+func (c camera) toWorld(sx, sy float32) physics.Vec2 {
+	return physics.Vec2{
+		X: c.center.X + (float64(sx)-c.x0-c.w/2)/c.scale,
+		Y: c.center.Y - (float64(sy)-c.y0-c.h/2)/c.scale,
+	}
+}
+
+// drawBodies 画出有质量天体和它们的尾迹，colorOf 给出第 k 个有质量天体的颜色
+func drawBodies(screen *ebiten.Image, cam camera, sys *physics.System, trails [][]physics.Vec2, showTrails bool, colorOf func(k int) color.RGBA) {
+	massive := sys.Massive()
+	if showTrails {
+		for k, i := range massive {
+			drawTrail(screen, cam, trails[i], colorOf(k))
+		}
+	}
+	for k, i := range massive {
+		b := &sys.Bodies[i]
+		x, y := cam.toScreen(b.Position)
+		r := math.Max(b.Radius*cam.scale, minRadius)
+		vector.DrawFilledCircle(screen, x, y, float32(r), colorOf(k), true)
+	}
+}
+
+// drawTrail 画一条逐渐变淡的尾迹
+func drawTrail(screen *ebiten.Image, cam camera, trail []physics.Vec2, c color.RGBA) {
+	for k := 1; k < len(trail); k++ {
+		alpha := float64(k) / float64(trailLength)
+		tc := color.RGBA{
+			uint8(float64(c.R) * alpha),
+			uint8(float64(c.G) * alpha),
+			uint8(float64(c.B) * alpha),
+			uint8(255 * alpha),
+		}
+		x0, y0 := cam.toScreen(trail[k-1])
+		x1, y1 := cam.toScreen(trail[k])
+		vector.StrokeLine(screen, x0, y0, x1, y1, 1, tc, false)
+	}
+}
+
+// drawArrow 画一支从 (x0, y0) 指向 (x1, y1) 的箭头
+func drawArrow(screen *ebiten.Image, x0, y0, x1, y1 float32, width float32, c color.Color) {
+	vector.StrokeLine(screen, x0, y0, x1, y1, width, c, true)
+	dx, dy := x1-x0, y1-y0
+	l := float32(math.Hypot(float64(dx), float64(dy)))
+	if l < 1 {
+		return
+	}
+	head := min(8, l/3)
+	ux, uy := dx/l, dy/l
+	// 箭头两翼与主干成 ±30°
+	const cos30, sin30 = 0.8660254, 0.5
+	vector.StrokeLine(screen, x1, y1, x1-head*(ux*cos30-uy*sin30), y1-head*(uy*cos30+ux*sin30), width, c, true)
+	vector.StrokeLine(screen, x1, y1, x1-head*(ux*cos30+uy*sin30), y1-head*(uy*cos30-ux*sin30), width, c, true)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+const (
+	restrictedPathLength = 20000 // 第三体保留的轨迹点数
+	zeroVelocityCell     = 4     // 零速度线采样间隔（像素）
+)
+
+var (
+	primaryColors  = [2]color.RGBA{{255, 220, 100, 255}, {120, 170, 255, 255}}
+	lagrangeColor  = color.RGBA{255, 120, 200, 255}
+	forbiddenColor = color.RGBA{60, 30, 40, 255}
+	zeroVelColor   = color.RGBA{200, 90, 110, 255}
+	thirdBodyColor = color.RGBA{230, 230, 230, 255}
+	thirdBodyTrail = color.RGBA{140, 200, 140, 255}
+)
+
+// restrictedGame 是圆型限制性三体问题的旋转系视图
+type restrictedGame struct {
+	cfg     config
+	sys     *physics.Restricted
+	cam     camera
+	jacobi0 float64
+	path    []physics.Vec2
+
+	background *ebiten.Image // 禁区和零速度线，只在重置或缩放时重画
+	paused     bool
+	sections   []*physics.Section // 每个粒子的庞加莱截面
+}
+
+// parseCoorbital 解析 -restricted 的参数：tadpole、horseshoe 或 "半径,角度(度)"
+func parseCoorbital(spec string) (radius, angle float64, err error) {
+	switch spec {
+	case "tadpole":
+		return 1.005, 70 * math.Pi / 180, nil
+	case "horseshoe":
+		return 1.008, math.Pi, nil
+	}
+	rText, aText, ok := strings.Cut(spec, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("-restricted wants tadpole, horseshoe or radius,angle; got %q", spec)
+	}
+	if radius, err = strconv.ParseFloat(rText, 64); err != nil {
+		return 0, 0, err
+	}
+	if angle, err = strconv.ParseFloat(aText, 64); err != nil {
+		return 0, 0, err
+	}
+	return radius, angle * math.Pi / 180, nil
+}
+
+// defaultSection 返回 -section auto 对应的截面：蝌蚪形轨道一直在 L4 附近、不穿过 y = 0，
+// 改用过 L4 的竖线；其他轨道用 y = 0
+func defaultSection(spec string, mu float64) string {
+	if spec == "tadpole" {
+		return fmt.Sprintf("x=%g,vx<0", 0.5-mu)
+	}
+	return "y=0,vy>0"
+}
+
+func newRestrictedGame(cfg config) (*restrictedGame, error) {
+	if cfg.mu <= 0 || cfg.mu > 0.5 {
+		return nil, fmt.Errorf("-mu must be in (0, 0.5], got %g", cfg.mu)
+	}
+	if _, _, err := parseCoorbital(cfg.restricted); err != nil {
+		return nil, err
+	}
+	if cfg.section == "auto" {
+		cfg.section = defaultSection(cfg.restricted, cfg.mu)
+	}
+	if cfg.section != "" {
+		if _, err := physics.ParseSection(cfg.section); err != nil {
+			return nil, err
+		}
+	}
+	g := &restrictedGame{cfg: cfg, cam: newCamera(0, 0, screenWidth, screenHeight, cfg.scale)}
+	g.Reset()
+	return g, nil
+}
+
+// Reset 重新放置第三体
+func (g *restrictedGame) Reset() {
+	radius, angle, _ := parseCoorbital(g.cfg.restricted)
+	g.sys = physics.NewRestricted(g.cfg.mu)
+	g.sys.AddCoorbital(radius, angle)
+	g.jacobi0 = g.sys.Jacobi(&g.sys.Particles[0])
+	g.path = nil
+	g.background = nil
+	g.resetSections()
+}
+
+func (g *restrictedGame) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.paused = !g.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.Reset()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		g.saveSections()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.cam.scale *= 1.25
+		g.background = nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.cam.scale /= 1.25
+		g.background = nil
+	}
+	if g.paused {
+		return nil
+	}
+	for k := 0; k < g.cfg.steps; k++ {
+		g.sys.Step(g.cfg.dt)
+		g.updateSections()
+	}
+	g.path = append(g.path, g.sys.Particles[0].Position)
+	if len(g.path) > restrictedPathLength {
+		g.path = g.path[1:]
+	}
+	return nil
+}
+
+// drawBackground 画出给定雅可比常数下的禁区（2Ω < C）和零速度线
+func (g *restrictedGame) drawBackground() *ebiten.Image {
+	img := ebiten.NewImage(screenWidth, screenHeight)
+	field := sampleGrid(0, 0, screenWidth, screenHeight, zeroVelocityCell, func(sx, sy float32) float64 {
+		return 2*g.sys.EffectivePotential(g.cam.toWorld(sx, sy)) - g.jacobi0
+	})
+	for j := range field.v {
+		for i, v := range field.v[j] {
+			if v < 0 {
+				x, y := float32(i)*field.cell-field.cell/2, float32(j)*field.cell-field.cell/2
+				vector.DrawFilledRect(img, x, y, field.cell, field.cell, forbiddenColor, false)
+			}
+		}
+	}
+	field.drawContour(img, 0, zeroVelColor)
+	return img
+}
+
+func (g *restrictedGame) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 20, 255})
+	if g.background == nil {
+		g.background = g.drawBackground()
+	}
+	screen.DrawImage(g.background, nil)
+
+	for k := 1; k < len(g.path); k++ {
+		x0, y0 := g.cam.toScreen(g.path[k-1])
+		x1, y1 := g.cam.toScreen(g.path[k])
+		vector.StrokeLine(screen, x0, y0, x1, y1, 1, thirdBodyTrail, false)
+	}
+
+	p1, p2 := g.sys.Primaries()
+	for k, p := range []physics.Vec2{p1, p2} {
+		x, y := g.cam.toScreen(p)
+		r := float32(math.Max(10*math.Cbrt([]float64{1 - g.sys.Mu, g.sys.Mu}[k]), minRadius))
+		vector.DrawFilledCircle(screen, x, y, r, primaryColors[k], true)
+	}
+	for k, lp := range g.sys.LagrangePoints() {
+		x, y := g.cam.toScreen(lp)
+		vector.StrokeLine(screen, x-4, y-4, x+4, y+4, 1, lagrangeColor, true)
+		vector.StrokeLine(screen, x-4, y+4, x+4, y-4, 1, lagrangeColor, true)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("L%d", k+1), int(x)+5, int(y)-16)
+	}
+
+	b := &g.sys.Particles[0]
+	x, y := g.cam.toScreen(b.Position)
+	vector.DrawFilledCircle(screen, x, y, 3, thirdBodyColor, true)
+
+	g.drawSections(screen)
+
+	cj := g.sys.Jacobi(b)
+	msg := fmt.Sprintf("restricted three-body (rotating frame)  mu = %g\n", g.sys.Mu)
+	msg += fmt.Sprintf("t = %.2f  C_J = %.10f  dC_J = %.2e\n", g.sys.Time, cj, cj-g.jacobi0)
+	if len(g.sections) > 0 {
+		msg += fmt.Sprintf("section crossings: %d\n", len(g.sections[0].Points))
+	}
+	msg += fmt.Sprintf("FPS: %0.1f  [space] pause  [r] reset  [s] save section  [+/-] zoom", ebiten.ActualFPS())
+	ebitenutil.DebugPrint(screen, msg)
+}
+
+func (g *restrictedGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
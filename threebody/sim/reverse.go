@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"threebody/physics"
+)
+
+// reversal 是 -reverse 模式的状态：正向积分 -reverse 指定的时间，
+// 速度反向后再积分同样的步数，回到起点时暂停并报告与初始条件的距离
+type reversal struct {
+	initial  *physics.System
+	steps    int // 单程步数
+	taken    int
+	distance float64
+	done     bool
+}
+
+// resetReverse 记下初始条件
+func (g *Game) resetReverse() {
+	g.reverse = nil
+	if g.cfg.reverse <= 0 {
+		return
+	}
+	g.reverse = &reversal{
+		initial: g.sys.Clone(),
+		steps:   max(1, int(g.cfg.reverse/g.cfg.dt+0.5)),
+	}
+}
+
+// stepReverse 在每一步（包括影子轨道和副本）之后调用，到达单程终点时反转速度，
+// 回到起点时暂停并返回 true
+func (g *Game) stepReverse() bool {
+	r := g.reverse
+	if r == nil || r.done {
+		return false
+	}
+	r.taken++
+	switch r.taken {
+	case r.steps:
+		g.flipAll()
+	case 2 * r.steps:
+		g.flipAll()
+		r.distance = physics.PhaseDistance(r.initial, g.sys, r.initial.Massive())
+		r.done = true
+		g.paused = true
+		log.Printf("reversibility: %s dt=%g T=%g distance %.3e", g.integ.Name(), g.cfg.dt, g.cfg.reverse, r.distance)
+		return true
+	}
+	return false
+}
+
+// flipAll 反转原始场景、李雅普诺夫影子轨道和所有副本的速度，
+// 事件检测从反转后的状态重新开始，不会把反转本身当成近心点或远心点
+func (g *Game) flipAll() {
+	g.sys.FlipVelocities()
+	if g.lyapunov != nil {
+		g.lyapunov.Shadow().FlipVelocities()
+	}
+	for _, t := range g.twins {
+		t.sys.FlipVelocities()
+	}
+	if g.events != nil {
+		g.events.Resync(g.sys)
+	}
+}
+
+// reverseHUD 返回往返检查的进度或结果
+func (g *Game) reverseHUD() string {
+	r := g.reverse
+	switch {
+	case r == nil:
+		return ""
+	case r.done:
+		return fmt.Sprintf("reversibility: back at start, phase distance %.3e\n", r.distance)
+	case r.taken < r.steps:
+		return fmt.Sprintf("reversibility: forward %d/%d steps\n", r.taken, r.steps)
+	default:
+		return fmt.Sprintf("reversibility: backward %d/%d steps\n", r.taken-r.steps, r.steps)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+// twinColors 是各个副本的颜色，副本 0 是未扰动的原始场景
+var twinColors = []color.RGBA{
+	{240, 240, 240, 255},
+	{255, 90, 90, 255},
+	{90, 200, 255, 255},
+	{255, 210, 60, 255},
+	{160, 255, 120, 255},
+	{230, 120, 255, 255},
+}
+
+// twin 是同一场景带微小扰动的副本，用来演示对初值的敏感性
+type twin struct {
+	sys    *physics.System
+	integ  physics.Integrator
+	trails [][]physics.Vec2
+
+	times []float64 // 与原始场景相空间距离的采样时间
+	seps  []float64
+}
+
+// resetTwins 从当前的原始场景重新生成副本
+func (g *Game) resetTwins() {
+	g.twins = nil
+	massive := g.sys.Massive()
+	if g.cfg.twins < 2 || len(massive) == 0 {
+		return
+	}
+	for k := 1; k < g.cfg.twins; k++ {
+		integ, _ := newIntegrator(g.integ.Name(), g.cfg)
+		t := &twin{
+			sys:    g.sys.Clone(),
+			integ:  integ,
+			trails: make([][]physics.Vec2, len(g.sys.Bodies)),
+		}
+		t.sys.Bodies[massive[0]].Position.X += float64(k) * g.cfg.perturb
+		g.twins = append(g.twins, t)
+	}
+}
+
+// updateTwins 记录副本的尾迹和它们与原始场景的相空间距离
+func (g *Game) updateTwins() {
+	massive := g.sys.Massive()
+	for _, t := range g.twins {
+		updateTrails(t.trails, t.sys)
+		t.times, t.seps = appendPoint(t.times, t.seps, g.sys.Time, physics.PhaseDistance(g.sys, t.sys, massive))
+	}
+}
+
+func twinColor(k int) color.RGBA {
+	return twinColors[k%len(twinColors)]
+}
+
+// drawTwins 把原始场景和所有副本叠加画出，每个副本一种颜色，
+// 并在右下角画出相空间距离随时间的变化（对数纵轴）
+func (g *Game) drawTwins(screen *ebiten.Image) {
+	for k := len(g.twins) - 1; k >= 0; k-- {
+		t := g.twins[k]
+		drawBodies(screen, g.cam, t.sys, t.trails, g.showTrails, func(int) color.RGBA { return twinColor(k + 1) })
+	}
+	drawBodies(screen, g.cam, g.sys, g.trails, g.showTrails, func(int) color.RGBA { return twinColor(0) })
+
+	lines := make([]series, len(g.twins))
+	for k, t := range g.twins {
+		lines[k] = series{xs: t.times, ys: t.seps, color: twinColor(k + 1)}
+	}
+	plot{x: screenWidth - 250, y: screenHeight - 140, w: 240, h: 100, title: "separation", logY: true}.draw(screen, lines...)
+}
+
+func (g *Game) twinsHUD() string {
+	if len(g.twins) == 0 {
+		return ""
+	}
+	msg := fmt.Sprintf("twins: %d copies, separation:", len(g.twins)+1)
+	for _, t := range g.twins {
+		if n := len(t.seps); n > 0 {
+			msg += fmt.Sprintf(" %.2e", t.seps[n-1])
+		}
+	}
+	return msg + "\n"
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"threebody/physics"
+)
+
+var (
+	velocityColor     = color.RGBA{120, 230, 255, 255}
+	accelerationColor = color.RGBA{255, 110, 110, 255}
+)
+
+// drawVectors 从每个有质量天体画出速度和合加速度箭头，以及可选的两两引力分量。
+// 箭头长度按模拟单位计算（速度乘 -vel-scale，加速度乘 -acc-scale），所以会随缩放一起变化。
+// 分量箭头用施力天体的颜色，只包含牛顿引力（PairAcceleration）。只有引力时
+// 它们首尾相接应正好拼成合加速度，拼不上说明力的计算有错（比如 main-kimi2.go 里除以了 other.mass）；
+// 用 -forces 加了库仑力、弹簧、阻力、背景场、1PN 或 PM 时，合加速度还包含这些项，两者本来就对不上。
+func (g *Game) drawVectors(screen *ebiten.Image) {
+	if !g.showVelocity && !g.showAcceleration && !g.showPairs {
+		return
+	}
+	g.pos = g.sys.Positions(g.pos)
+	g.vel = g.sys.Velocities(g.vel)
+	if cap(g.acc) < len(g.pos) {
+		g.acc = make([]physics.Vec2, len(g.pos))
+	}
+	g.acc = g.acc[:len(g.pos)]
+	g.sys.Accelerations(g.pos, g.vel, g.acc)
+
+	massive := g.sys.Massive()
+	for _, i := range massive {
+		b := &g.sys.Bodies[i]
+		x0, y0 := g.cam.toScreen(b.Position)
+		if g.showVelocity {
+			x1, y1 := g.cam.toScreen(b.Position.Add(b.Velocity.Mult(g.cfg.velScale)))
+			drawArrow(screen, x0, y0, x1, y1, 2, velocityColor)
+		}
+		if g.showPairs {
+			// 分量依次首尾相接
+			tail := b.Position
+			for k, j := range massive {
+				tip := tail.Add(g.sys.PairAcceleration(i, j).Mult(g.cfg.accScale))
+				if j != i {
+					tx, ty := g.cam.toScreen(tail)
+					hx, hy := g.cam.toScreen(tip)
+					drawArrow(screen, tx, ty, hx, hy, 1, bodyColors[k%len(bodyColors)])
+				}
+				tail = tip
+			}
+		}
+		if g.showAcceleration {
+			x1, y1 := g.cam.toScreen(b.Position.Add(g.acc[i].Mult(g.cfg.accScale)))
+			drawArrow(screen, x0, y0, x1, y1, 2, accelerationColor)
+		}
+	}
+}
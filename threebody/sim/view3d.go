@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"threebody/physics"
+)
+
+const (
+	gridExtent   = 5    // 参考网格覆盖 xy 平面上的 [-gridExtent, gridExtent]
+	rotateSpeed  = 0.03 // 方向键每帧旋转的弧度
+	dragSpeed    = 0.01 // 鼠标拖动每像素旋转的弧度
+	viewDistance = 12   // 透视投影时相机到原点的距离（模拟单位）
+)
+
+var gridColor = color.RGBA{50, 50, 80, 255}
+
+// camera3 是可旋转的三维相机：先绕 z 轴转 yaw，再绕 x 轴转 pitch，
+// 之后沿视线方向做正交或透视投影
+type camera3 struct {
+	yaw, pitch  float64
+	scale       float64 // 每个模拟单位对应的像素数
+	perspective bool
+}
+
+// project 返回 p 的屏幕坐标、深度（越大越靠近观察者）和透视缩放系数。
+// 透视时位于相机后方的点 ok 为 false。
+func (c camera3) project(p physics.Vec3) (x, y float32, depth, k float64, ok bool) {
+	q := p.RotateZ(c.yaw).RotateX(c.pitch)
+	k = 1
+	if c.perspective {
+		if q.Z >= viewDistance-0.1 {
+			return 0, 0, q.Z, 0, false
+		}
+		k = viewDistance / (viewDistance - q.Z)
+	}
+	x = float32(screenWidth/2 + q.X*c.scale*k)
+	y = float32(screenHeight/2 - q.Y*c.scale*k)
+	return x, y, q.Z, k, true
+}
+
+// game3d 是三维 N 体模式
+type game3d struct {
+	cfg     config
+	preset  physics.Preset3
+	sys     *physics.System3
+	integ   physics.Integrator3
+	energy0 float64
+	l0      physics.Vec3
+	cam     camera3
+	trails  [][]physics.Vec3
+
+	paused     bool
+	showTrails bool
+	binary     physics.Elements3 // 本帧的最紧密束缚对，见 updateElements
+	binaryOK   bool
+	dragX      int // 鼠标拖动的上一个位置
+	dragY      int
+}
+
+func newGame3D(cfg config) (*game3d, error) {
+	// 三维模式只有牛顿引力和 N 体本身，二维模式的附加功能都没有
+	if err := rejectFlags("-3d", "forces", "charges", "mass-loss", "particles", "planet", "twins",
+		"events", "on-escape", "lyapunov", "reverse", "field", "svg-out"); err != nil {
+		return nil, err
+	}
+	p, err := lookupPreset3(cfg)
+	if err != nil {
+		return nil, err
+	}
+	integ, err := physics.NewIntegrator3(cfg.integrator)
+	if err != nil {
+		return nil, err
+	}
+	g := &game3d{
+		cfg:        cfg,
+		preset:     p,
+		integ:      integ,
+		cam:        camera3{pitch: -math.Pi / 3, scale: cfg.scale, perspective: true},
+		showTrails: cfg.cluster == "",
+	}
+	g.Reset()
+	return g, nil
+}
+
+// Reset 重新生成初始条件
+func (g *game3d) Reset() {
+	g.sys = g.preset.New()
+	g.sys.Softening = g.cfg.softening
+	g.energy0 = g.sys.Energy()
+	g.l0 = g.sys.AngularMomentum()
+	g.trails = make([][]physics.Vec3, len(g.sys.Bodies))
+}
+
+func (g *game3d) handleInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.paused = !g.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.Reset()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		g.showTrails = !g.showTrails
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		g.cam.perspective = !g.cam.perspective
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.cam.scale *= 1.25
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.cam.scale /= 1.25
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		g.cam.yaw -= rotateSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		g.cam.yaw += rotateSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		g.cam.pitch -= rotateSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		g.cam.pitch += rotateSpeed
+	}
+	x, y := ebiten.CursorPosition()
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		g.cam.yaw += float64(x-g.dragX) * dragSpeed
+		g.cam.pitch += float64(y-g.dragY) * dragSpeed
+	}
+	g.dragX, g.dragY = x, y
+	g.cam.pitch = math.Max(-math.Pi, math.Min(0, g.cam.pitch))
+}
+
+// updateElements 每帧找一次最紧密束缚对。N 体团或天体太多时不找，和二维模式一样
+func (g *game3d) updateElements() {
+	g.binaryOK = false
+	if !g.cfg.elements || g.cfg.cluster != "" || len(g.sys.Massive()) > maxElementsBodies {
+		return
+	}
+	g.binary, g.binaryOK = g.sys.TightestPair()
+}
+
+func (g *game3d) Update() error {
+	g.handleInput()
+	defer g.updateElements()
+	if g.paused {
+		return nil
+	}
+	for k := 0; k < g.cfg.steps; k++ {
+		g.integ.Step(g.sys, g.cfg.dt)
+	}
+	for _, i := range g.sys.Massive() {
+		g.trails[i] = append(g.trails[i], g.sys.Bodies[i].Position)
+		if len(g.trails[i]) > trailLength {
+			g.trails[i] = g.trails[i][1:]
+		}
+	}
+	return nil
+}
+
+// drawGrid 画出 xy 平面上的参考网格，帮助分辨方向
+func (g *game3d) drawGrid(screen *ebiten.Image) {
+	for k := -gridExtent; k <= gridExtent; k++ {
+		for _, seg := range [2][2]physics.Vec3{
+			{{X: float64(k), Y: -gridExtent}, {X: float64(k), Y: gridExtent}},
+			{{X: -gridExtent, Y: float64(k)}, {X: gridExtent, Y: float64(k)}},
+		} {
+			x0, y0, _, _, ok0 := g.cam.project(seg[0])
+			x1, y1, _, _, ok1 := g.cam.project(seg[1])
+			if ok0 && ok1 {
+				vector.StrokeLine(screen, x0, y0, x1, y1, 1, gridColor, false)
+			}
+		}
+	}
+}
+
+func (g *game3d) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 20, 255})
+	g.drawGrid(screen)
+
+	massive := g.sys.Massive()
+	if g.showTrails {
+		for k, i := range massive {
+			c := bodyColors[k%len(bodyColors)]
+			trail := g.trails[i]
+			for n := 1; n < len(trail); n++ {
+				x0, y0, _, _, ok0 := g.cam.project(trail[n-1])
+				x1, y1, _, _, ok1 := g.cam.project(trail[n])
+				if !ok0 || !ok1 {
+					continue
+				}
+				a := float64(n) / float64(trailLength)
+				tc := color.RGBA{uint8(float64(c.R) * a), uint8(float64(c.G) * a), uint8(float64(c.B) * a), uint8(255 * a)}
+				vector.StrokeLine(screen, x0, y0, x1, y1, 1, tc, false)
+			}
+		}
+	}
+
+	// 按深度从远到近画，近处的天体盖住远处的
+	type sprite struct {
+		x, y  float32
+		r     float32
+		depth float64
+		c     color.RGBA
+	}
+	var sprites []sprite
+	for i := range g.sys.Bodies {
+		b := &g.sys.Bodies[i]
+		x, y, depth, k, ok := g.cam.project(b.Position)
+		if !ok {
+			continue
+		}
+		c, r := particleColor, float32(1)
+		if !b.IsTest() {
+			c = bodyColors[sort.SearchInts(massive, i)%len(bodyColors)]
+			r = float32(math.Max(b.Radius*g.cam.scale*k, minRadius))
+		}
+		sprites = append(sprites, sprite{x, y, r, depth, c})
+	}
+	sort.Slice(sprites, func(a, b int) bool { return sprites[a].depth < sprites[b].depth })
+	for _, s := range sprites {
+		vector.DrawFilledCircle(screen, s.x, s.y, s.r, s.c, true)
+	}
+
+	e := g.sys.Energy()
+	drift := 0.0
+	if g.energy0 != 0 {
+		drift = (e - g.energy0) / math.Abs(g.energy0)
+	}
+	dl := 0.0
+	if l := g.l0.Length(); l != 0 {
+		dl = g.sys.AngularMomentum().Sub(g.l0).Length() / l
+	}
+	proj := "orthographic"
+	if g.cam.perspective {
+		proj = "perspective"
+	}
+	msg := fmt.Sprintf("3D preset: %s  integrator: %s  dt: %g  view: %s\n", g.preset.Name, g.integ.Name(), g.cfg.dt, proj)
+	msg += fmt.Sprintf("t = %.3f  E = %.6f  dE/E0 = %.2e  |dL|/|L0| = %.2e\n", g.sys.Time, e, drift, dl)
+	if el := g.binary; g.binaryOK {
+		msg += fmt.Sprintf("binary %s-%s: a = %.4f  e = %.4f  i = %.1f deg\n",
+			g.sys.Bodies[el.I].Name, g.sys.Bodies[el.J].Name, el.SemiMajor, el.Eccentricity, el.Inclination*180/math.Pi)
+	}
+	msg += fmt.Sprintf("FPS: %0.1f  [space] pause  [r] reset  [t] trails  [p] projection  [arrows/drag] rotate  [+/-] zoom", ebiten.ActualFPS())
+	ebitenutil.DebugPrint(screen, msg)
+}
+
+func (g *game3d) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
@@ -0,0 +1,134 @@
+// Package svgplot 把 physics.Trajectories 记录的完整轨迹画成 SVG，
+// 用于文档和打印：每个天体一条折线，起点画空心圆、终点画实心圆，
+// 右下角是以模拟单位标注的比例尺。
+package svgplot
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+
+	"threebody/physics"
+)
+
+// Options 控制输出的大小和样式，零值字段取默认值
+type Options struct {
+	Width, Height int     // 画布大小（像素），默认 800×800
+	Margin        int     // 四周留白（像素），默认 40
+	StrokeWidth   float64 // 轨迹线宽，默认 1.5
+	Title         string  // 左上角的标题，为空时不画
+}
+
+// Colors 是各条轨迹依次使用的颜色，色相顺序和 sim 里的天体相同，调暗了一些以便白底打印
+var Colors = []string{"#d03c3c", "#2f9e44", "#3b5bdb", "#e8a200", "#9c36b5", "#1098ad"}
+
+// maxLegend 是画图例的最多天体数
+const maxLegend = 12
+
+func (o *Options) defaults() {
+	if o.Width == 0 {
+		o.Width = 800
+	}
+	if o.Height == 0 {
+		o.Height = 800
+	}
+	if o.Margin == 0 {
+		o.Margin = 40
+	}
+	if o.StrokeWidth == 0 {
+		o.StrokeWidth = 1.5
+	}
+}
+
+// Write 把 t 的所有轨迹写成 SVG。坐标按等比例缩放到画布里，y 轴向上；
+// 相邻点在画布上距离不到半个像素时合并，文件大小和步数无关。
+func Write(w io.Writer, t *physics.Trajectories, opt Options) error {
+	opt.defaults()
+	lo, hi, ok := t.Bounds()
+	if !ok {
+		return fmt.Errorf("no trajectory points to plot")
+	}
+	span := math.Max(hi.X-lo.X, hi.Y-lo.Y)
+	if span == 0 {
+		span = 1
+	}
+	inner := float64(min(opt.Width, opt.Height) - 2*opt.Margin)
+	if inner <= 0 {
+		return fmt.Errorf("%dx%d image leaves no room inside a %d px margin", opt.Width, opt.Height, opt.Margin)
+	}
+	scale := inner / span
+	center := lo.Add(hi).Mult(0.5)
+	toSVG := func(p physics.Vec2) (float64, float64) {
+		return float64(opt.Width)/2 + (p.X-center.X)*scale, float64(opt.Height)/2 - (p.Y-center.Y)*scale
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		opt.Width, opt.Height, opt.Width, opt.Height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	if opt.Title != "" {
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="14">%s</text>`+"\n", opt.Margin/2, opt.Margin/2+6, html.EscapeString(opt.Title))
+	}
+
+	for k, path := range t.Paths {
+		if len(path) == 0 {
+			continue
+		}
+		color := Colors[k%len(Colors)]
+		name := html.EscapeString(t.Names[k])
+		fmt.Fprintf(bw, `<g id="body-%d"><title>%s</title>`+"\n", k, name)
+		fmt.Fprintf(bw, `<polyline fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round" points="`, color, opt.StrokeWidth)
+		lastX, lastY := math.Inf(1), math.Inf(1)
+		for n, p := range path {
+			x, y := toSVG(p)
+			if n != len(path)-1 && math.Hypot(x-lastX, y-lastY) < 0.5 {
+				continue
+			}
+			fmt.Fprintf(bw, "%.2f,%.2f ", x, y)
+			lastX, lastY = x, y
+		}
+		fmt.Fprintln(bw, `"/>`)
+		// 起点空心、终点实心
+		x0, y0 := toSVG(path[0])
+		x1, y1 := toSVG(path[len(path)-1])
+		fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="4" fill="white" stroke="%s" stroke-width="1.5"/>`+"\n", x0, y0, color)
+		fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="4" fill="%s"/>`+"\n", x1, y1, color)
+		fmt.Fprintln(bw, `</g>`)
+
+		// 图例，天体太多时不画
+		if len(t.Paths) > maxLegend {
+			continue
+		}
+		ly := opt.Margin + 16*k
+		fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"/>`+"\n",
+			opt.Width-opt.Margin-110, ly, opt.Width-opt.Margin-90, ly, color)
+		fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`+"\n", opt.Width-opt.Margin-84, ly+4, name)
+	}
+
+	// 比例尺：取 1、2、5 乘 10 的幂里不超过画布五分之一的最大长度
+	length := niceLength(span / 5)
+	px := length * scale
+	bx := float64(opt.Width-opt.Margin) - px
+	by := float64(opt.Height - opt.Margin/2)
+	fmt.Fprintf(bw, `<g stroke="black" stroke-width="1.5"><line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/>`, bx, by, bx+px, by)
+	fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/>`, bx, by-4, bx, by+4)
+	fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/></g>`+"\n", bx+px, by-4, bx+px, by+4)
+	fmt.Fprintf(bw, `<text x="%.2f" y="%.2f" text-anchor="middle">%g units</text>`+"\n", bx+px/2, by-8, length)
+	fmt.Fprintf(bw, `<text x="%d" y="%.2f">t = %.4g to %.4g</text>`+"\n", opt.Margin/2, by, t.Start, t.End)
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// niceLength 返回不超过 x 的最大的 1、2、5 × 10^k
+func niceLength(x float64) float64 {
+	p := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, m := range []float64{5, 2, 1} {
+		if m*p <= x {
+			return m * p
+		}
+	}
+	return p
+}
@@ -0,0 +1,73 @@
+package svgplot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"threebody/physics"
+)
+
+func TestWrite(t *testing.T) {
+	p, _ := physics.LookupPreset("figure8")
+	s := p.New()
+	tr := physics.NewTrajectories(s)
+	integ := &physics.Verlet{}
+	for k := 0; k < 6300; k++ {
+		integ.Step(s, 0.001)
+		tr.Record(s)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tr, Options{Title: "figure-8 & friends"}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "<polyline"); n != 3 {
+		t.Errorf("%d polylines, want 3", n)
+	}
+	if n := strings.Count(out, "<circle"); n != 6 {
+		t.Errorf("%d markers, want 6", n)
+	}
+	// 八字形轨道宽约 2.2，比例尺取 0.2
+	if !strings.Contains(out, ">0.2 units<") {
+		t.Errorf("scale bar label missing")
+	}
+	// 接近一个周期的轨迹合并掉相邻的点后应当远少于 6300 个
+	if n := strings.Count(out, ","); n > 3*3000 {
+		t.Errorf("%d points written, expected thinning", n)
+	}
+
+	d := xml.NewDecoder(&buf)
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid XML: %v", err)
+		}
+	}
+}
+
+func TestNiceLength(t *testing.T) {
+	for x, want := range map[float64]float64{0.44: 0.2, 1: 1, 3.7: 2, 9.99: 5, 120: 100} {
+		if got := niceLength(x); got != want {
+			t.Errorf("niceLength(%g) = %g, want %g", x, got, want)
+		}
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	if err := Write(io.Discard, &physics.Trajectories{}, Options{}); err == nil {
+		t.Errorf("expected an error for empty trajectories")
+	}
+}
+
+func TestWriteTooSmall(t *testing.T) {
+	p, _ := physics.LookupPreset("figure8")
+	tr := physics.NewTrajectories(p.New())
+	if err := Write(io.Discard, tr, Options{Width: 60, Height: 60}); err == nil {
+		t.Errorf("expected an error for a canvas smaller than the margins")
+	}
+}